@@ -20,25 +20,42 @@ along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/Fogity/TS4Libs/script"
+	"github.com/Fogity/TS4Tools/logging"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Printf("Expecting 1 argument, found %v\n", len(os.Args)-1)
-		return
+	level := flag.String("log-level", "info", "minimum `level` of logged messages: debug, info, warn or error")
+	jsonLines := flag.Bool("log-json", false, "log messages as JSON lines")
+	flag.Parse()
+
+	l, err := logging.ParseLevel(*level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	sink := logging.NewTextSink(os.Stderr)
+	if *jsonLines {
+		sink = logging.NewJSONSink(os.Stderr)
+	}
+	logging.SetDefault(logging.New(l, sink))
+
+	if flag.NArg() != 1 {
+		logging.Error("Expecting 1 argument", "found", flag.NArg())
+		os.Exit(2)
 	}
 
-	fmt.Printf("Running script...\n")
+	logging.Info("Running script", "file", flag.Arg(0))
 
-	err := script.RunFile(os.Args[1])
+	err = script.RunFile(flag.Arg(0))
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		logging.Error("Script failed", "file", flag.Arg(0), "error", err)
+		os.Exit(1)
 	}
 
-	fmt.Printf("Script run sucessfully.\n")
+	logging.Info("Script run sucessfully", "file", flag.Arg(0))
 }
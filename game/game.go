@@ -0,0 +1,256 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	BaseGame = "BaseGame"
+
+	KindBase      = "Base Game"
+	KindExpansion = "Expansion Pack"
+	KindGame      = "Game Pack"
+	KindStuff     = "Stuff Pack"
+	KindFree      = "Free Pack"
+
+	ClientFullBuild      = "ClientFullBuild0.package"
+	ClientDeltaBuild     = "ClientDeltaBuild0.package"
+	SimulationFullBuild  = "SimulationFullBuild0.package"
+	SimulationDeltaBuild = "SimulationDeltaBuild0.package"
+)
+
+var (
+	matchPack    = regexp.MustCompile("^(EP|GP|SP|FP)[0-9]+$")
+	matchStrings = regexp.MustCompile("^Strings_([A-Z]{3}_[A-Z]{2})\\.package$")
+
+	kinds = map[string]string{
+		"EP": KindExpansion,
+		"GP": KindGame,
+		"SP": KindStuff,
+		"FP": KindFree,
+	}
+)
+
+type Pack struct {
+	Name, Kind string
+	Dirs       []string
+	Locales    []string
+}
+
+type Install struct {
+	Dir     string
+	Packs   []*Pack
+	Missing []string
+}
+
+func IsPack(name string) bool {
+	return matchPack.MatchString(name)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func (p *Pack) IsBase() bool {
+	return p.Name == BaseGame
+}
+
+func (p *Pack) Find(name string) (string, bool) {
+	for _, dir := range p.Dirs {
+		path := filepath.Join(dir, name)
+		if isFile(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func (p *Pack) FindAll(match *regexp.Regexp) []string {
+	paths := make([]string, 0)
+	for _, dir := range p.Dirs {
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, info := range infos {
+			if !info.IsDir() && match.MatchString(info.Name()) {
+				paths = append(paths, filepath.Join(dir, info.Name()))
+			}
+		}
+	}
+	return paths
+}
+
+func (p *Pack) HasLocale(language string) bool {
+	for _, l := range p.Locales {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Pack) scanLocales() {
+	found := make(map[string]bool)
+	for _, dir := range p.Dirs {
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, info := range infos {
+			if m := matchStrings.FindStringSubmatch(info.Name()); m != nil {
+				found[m[1]] = true
+			}
+		}
+	}
+	for l := range found {
+		p.Locales = append(p.Locales, l)
+	}
+	sort.Strings(p.Locales)
+}
+
+func (i *Install) missing(format string, a ...interface{}) {
+	i.Missing = append(i.Missing, fmt.Sprintf(format, a...))
+}
+
+func Open(dir string) (*Install, error) {
+	if !isDir(dir) {
+		return nil, fmt.Errorf("%v is not a directory.", dir)
+	}
+
+	i := &Install{Dir: dir}
+
+	base := &Pack{
+		Name: BaseGame,
+		Kind: KindBase,
+		Dirs: []string{
+			filepath.Join(dir, "Data", "Client"),
+			filepath.Join(dir, "Data", "Simulation"),
+		},
+	}
+	if !isDir(base.Dirs[0]) {
+		return nil, fmt.Errorf("%v does not contain a Sims 4 installation, Data/Client is missing.", dir)
+	}
+	for _, name := range []string{ClientFullBuild, ClientDeltaBuild, SimulationFullBuild, SimulationDeltaBuild} {
+		if _, ok := base.Find(name); !ok {
+			i.missing("%v: %v", base.Name, name)
+		}
+	}
+	base.scanLocales()
+	i.Packs = append(i.Packs, base)
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range infos {
+		if !info.IsDir() || !IsPack(info.Name()) {
+			continue
+		}
+		name := info.Name()
+		pack := &Pack{
+			Name: name,
+			Kind: kinds[name[:2]],
+		}
+		delta := filepath.Join(dir, "Delta", name)
+		if isDir(delta) {
+			pack.Dirs = append(pack.Dirs, delta)
+		} else {
+			i.missing("%v: Delta folder", name)
+		}
+		pack.Dirs = append(pack.Dirs, filepath.Join(dir, name))
+		for _, name := range []string{ClientFullBuild, SimulationFullBuild} {
+			if _, ok := pack.Find(name); !ok {
+				i.missing("%v: %v", pack.Name, name)
+			}
+		}
+		pack.scanLocales()
+		i.Packs = append(i.Packs, pack)
+	}
+
+	return i, nil
+}
+
+func (i *Install) Base() *Pack {
+	return i.Packs[0]
+}
+
+func (i *Install) Pack(name string) *Pack {
+	for _, p := range i.Packs {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (i *Install) Locales() []string {
+	found := make(map[string]bool)
+	for _, p := range i.Packs {
+		for _, l := range p.Locales {
+			found[l] = true
+		}
+	}
+	locales := make([]string, 0, len(found))
+	for l := range found {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func (i *Install) Summary() string {
+	return fmt.Sprintf("Found base game and %v packs.", len(i.Packs)-1)
+}
+
+func (i *Install) Report() string {
+	lines := []string{fmt.Sprintf("Game directory: %v", i.Dir)}
+	for _, p := range i.Packs {
+		locales := strings.Join(p.Locales, ", ")
+		if locales == "" {
+			locales = "none"
+		}
+		lines = append(lines, fmt.Sprintf("  %-8v %-14v locales: %v", p.Name, p.Kind, locales))
+	}
+	if len(i.Missing) == 0 {
+		lines = append(lines, "Nothing missing.")
+	} else {
+		lines = append(lines, "Missing:")
+		for _, m := range i.Missing {
+			lines = append(lines, "  "+m)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package logging
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+var ErrLevelUnknown = errors.New("Unknown log level, expecting debug, info, warn or error.")
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return "unknown"
+	}
+	return levelNames[l]
+}
+
+func ParseLevel(text string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(text, name) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, ErrLevelUnknown
+}
+
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+type Sink interface {
+	Log(r *Record)
+}
+
+type Logger struct {
+	level  Level
+	sinks  []Sink
+	fields []Field
+	mutex  *sync.Mutex
+}
+
+func New(level Level, sinks ...Sink) *Logger {
+	return &Logger{level, sinks, nil, new(sync.Mutex)}
+}
+
+func fields(keyvals []interface{}) []Field {
+	fs := make([]Field, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok || i+1 == len(keyvals) {
+			fs = append(fs, Field{"extra", keyvals[i]})
+			i--
+			continue
+		}
+		fs = append(fs, Field{key, keyvals[i+1]})
+	}
+	return fs
+}
+
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	w := *l
+	w.fields = append(append([]Field(nil), l.fields...), fields(keyvals)...)
+	return &w
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level && len(l.sinks) > 0
+}
+
+func (l *Logger) Log(level Level, message string, keyvals ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	r := &Record{time.Now(), level, message, append(append([]Field(nil), l.fields...), fields(keyvals)...)}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, s := range l.sinks {
+		s.Log(r)
+	}
+}
+
+func (l *Logger) Debug(message string, keyvals ...interface{}) {
+	l.Log(LevelDebug, message, keyvals...)
+}
+
+func (l *Logger) Info(message string, keyvals ...interface{}) {
+	l.Log(LevelInfo, message, keyvals...)
+}
+
+func (l *Logger) Warn(message string, keyvals ...interface{}) {
+	l.Log(LevelWarn, message, keyvals...)
+}
+
+func (l *Logger) Error(message string, keyvals ...interface{}) {
+	l.Log(LevelError, message, keyvals...)
+}
+
+var (
+	defaultMutex  sync.RWMutex
+	defaultLogger = New(LevelError)
+)
+
+func SetDefault(l *Logger) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultLogger = l
+}
+
+func Default() *Logger {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultLogger
+}
+
+func With(keyvals ...interface{}) *Logger {
+	return Default().With(keyvals...)
+}
+
+func Debug(message string, keyvals ...interface{}) {
+	Default().Log(LevelDebug, message, keyvals...)
+}
+
+func Info(message string, keyvals ...interface{}) {
+	Default().Log(LevelInfo, message, keyvals...)
+}
+
+func Warn(message string, keyvals ...interface{}) {
+	Default().Log(LevelWarn, message, keyvals...)
+}
+
+func Error(message string, keyvals ...interface{}) {
+	Default().Log(LevelError, message, keyvals...)
+}
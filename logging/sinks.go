@@ -0,0 +1,158 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const timeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+func value(v interface{}) interface{} {
+	switch x := v.(type) {
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
+
+func FormatText(r *Record) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v %-5v %v", r.Time.Format(timeFormat), strings.ToUpper(r.Level.String()), r.Message)
+	for _, f := range r.Fields {
+		text := fmt.Sprint(value(f.Value))
+		if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+			text = strconv.Quote(text)
+		}
+		fmt.Fprintf(&b, " %v=%v", f.Key, text)
+	}
+	return b.String()
+}
+
+func FormatJSON(r *Record) string {
+	var b strings.Builder
+	b.WriteString("{")
+	write := func(key string, v interface{}) {
+		k, _ := json.Marshal(key)
+		data, err := json.Marshal(v)
+		if err != nil {
+			data, _ = json.Marshal(fmt.Sprint(v))
+		}
+		if b.Len() > 1 {
+			b.WriteString(",")
+		}
+		b.Write(k)
+		b.WriteString(":")
+		b.Write(data)
+	}
+	write("time", r.Time.Format(time.RFC3339Nano))
+	write("level", r.Level.String())
+	write("msg", r.Message)
+	for _, f := range r.Fields {
+		write(f.Key, value(f.Value))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+type writerSink struct {
+	w      io.Writer
+	format func(r *Record) string
+}
+
+func (s *writerSink) Log(r *Record) {
+	io.WriteString(s.w, s.format(r)+"\n")
+}
+
+func NewTextSink(w io.Writer) Sink {
+	return &writerSink{w, FormatText}
+}
+
+func NewJSONSink(w io.Writer) Sink {
+	return &writerSink{w, FormatJSON}
+}
+
+type levelSink struct {
+	level Level
+	sink  Sink
+}
+
+func (s *levelSink) Log(r *Record) {
+	if r.Level >= s.level {
+		s.sink.Log(r)
+	}
+}
+
+func AtLevel(level Level, sink Sink) Sink {
+	return &levelSink{level, sink}
+}
+
+type Buffer struct {
+	mutex   sync.Mutex
+	max     int
+	lines   []string
+	changed func(text string)
+	notify  chan struct{}
+}
+
+func NewBuffer(max int, changed func(text string)) *Buffer {
+	b := &Buffer{max: max, changed: changed}
+	if changed != nil {
+		b.notify = make(chan struct{}, 1)
+		go b.run()
+	}
+	return b
+}
+
+func (b *Buffer) run() {
+	for range b.notify {
+		b.changed(b.String())
+	}
+}
+
+func (b *Buffer) Log(r *Record) {
+	b.mutex.Lock()
+	b.lines = append(b.lines, FormatText(r))
+	if len(b.lines) > b.max {
+		b.lines = b.lines[len(b.lines)-b.max:]
+	}
+	b.mutex.Unlock()
+
+	if b.notify != nil {
+		select {
+		case b.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *Buffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return strings.Join(b.lines, "\n")
+}
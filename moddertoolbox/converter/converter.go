@@ -20,102 +20,156 @@ along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
 package converter
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 
-	"gopkg.in/qml.v1"
+	"github.com/Fogity/TS4Tools/logging"
 )
 
 const (
 	formatHex32 = "0x%08X"
 	formatHex64 = "0x%016X"
-	formatDec32 = "%v"
-	formatDec64 = "%v"
+	formatBin32 = "0b%032b"
+	formatBin64 = "0b%064b"
+	formatOct   = "0o%o"
+	formatDec   = "%v"
+	formatByte  = "%02X"
 
-	resultInputInvalid  = "Invalid input"
-	resultInputTooLarge = "Number too large"
+	outOfRange = "-"
 )
 
 var (
-	matchHex = regexp.MustCompile("^0x[0-9a-fA-F]+$")
-	matchDec = regexp.MustCompile("^[0-9]+$")
+	ErrInputInvalid  = errors.New("Invalid input")
+	ErrInputTooLarge = errors.New("Number too large")
 )
 
-type Data struct {
-	Result     string
-	AlignRight bool
+var (
+	matchHex   = regexp.MustCompile("^0[xX][0-9a-fA-F]+$")
+	matchBin   = regexp.MustCompile("^0[bB][01]+$")
+	matchOct   = regexp.MustCompile("^0[oO][0-7]+$")
+	matchDec   = regexp.MustCompile("^-?[0-9]+$")
+	matchBytes = regexp.MustCompile("^[0-9a-fA-F]{2}([ -][0-9a-fA-F]{2})+$")
+	matchOrder = regexp.MustCompile("^(?i:le|be):\\s*[0-9a-fA-F]{2}([ -]?[0-9a-fA-F]{2})*$")
+)
+
+type Result struct {
+	Bits                    int
+	Unsigned32, Signed32    string
+	Unsigned64, Signed64    string
+	Hex, Binary, Octal      string
+	LittleEndian, BigEndian string
 }
 
-func (d *Data) ChangeText(text string) {
-	str := strings.TrimSpace(text)
+func newResult(value uint64, bits int) *Result {
+	r := &Result{Bits: bits}
+
+	if bits == 32 {
+		r.Unsigned32 = fmt.Sprintf(formatDec, uint32(value))
+		r.Signed32 = fmt.Sprintf(formatDec, int32(value))
+		r.Hex = fmt.Sprintf(formatHex32, uint32(value))
+		r.Binary = fmt.Sprintf(formatBin32, uint32(value))
+		r.Octal = fmt.Sprintf(formatOct, uint32(value))
+	} else {
+		r.Unsigned32 = outOfRange
+		r.Signed32 = outOfRange
+		r.Hex = fmt.Sprintf(formatHex64, value)
+		r.Binary = fmt.Sprintf(formatBin64, value)
+		r.Octal = fmt.Sprintf(formatOct, value)
+	}
 
-	if str == "" {
-		d.Update("", false)
-		return
+	r.Unsigned64 = fmt.Sprintf(formatDec, value)
+	r.Signed64 = fmt.Sprintf(formatDec, int64(value))
+
+	le := make([]string, bits/8)
+	be := make([]string, bits/8)
+	for i := range le {
+		b := fmt.Sprintf(formatByte, byte(value>>(8*uint(i))))
+		le[i] = b
+		be[len(be)-1-i] = b
 	}
+	r.LittleEndian = strings.Join(le, " ")
+	r.BigEndian = strings.Join(be, " ")
 
-	if matchHex.MatchString(str) {
-		var ui32 uint32
-		if _, err := fmt.Sscan(str, &ui32); err == nil {
-			d.Update(fmt.Sprintf(formatDec32, ui32), true)
-			return
-		}
+	return r
+}
 
-		var ui64 uint64
-		if _, err := fmt.Sscan(str, &ui64); err == nil {
-			d.Update(fmt.Sprintf(formatDec64, ui64), true)
-			return
-		}
+func parseUnsigned(str string, base int) (*Result, error) {
+	value, err := strconv.ParseUint(str, base, 64)
+	if err != nil {
+		return nil, ErrInputTooLarge
+	}
+	if value <= math.MaxUint32 {
+		return newResult(value, 32), nil
+	}
+	return newResult(value, 64), nil
+}
 
-		d.Update(resultInputTooLarge, false)
-		return
+func parseBytes(str string) (*Result, error) {
+	bigEndian := false
+	if i := strings.Index(str, ":"); i >= 0 {
+		bigEndian = strings.EqualFold(str[:i], "be")
+		str = str[i+1:]
 	}
 
-	if matchDec.MatchString(str) {
-		str = strings.TrimLeft(str, "0")
-		if str == "" {
-			str = "0"
-		}
+	digits := strings.NewReplacer(" ", "", "\t", "", "-", "").Replace(str)
+	if len(digits) > 16 {
+		return nil, ErrInputTooLarge
+	}
 
-		var ui32 uint32
-		if _, err := fmt.Sscan(str, &ui32); err == nil {
-			d.Update(fmt.Sprintf(formatHex32, ui32), true)
-			return
+	var value uint64
+	count := len(digits) / 2
+	for i := 0; i < count; i++ {
+		b, err := strconv.ParseUint(digits[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return nil, ErrInputInvalid
 		}
-
-		var ui64 uint64
-		if _, err := fmt.Sscan(str, &ui64); err == nil {
-			d.Update(fmt.Sprintf(formatHex64, ui64), true)
-			return
+		if bigEndian {
+			value = value<<8 | b
+		} else {
+			value |= b << (8 * uint(i))
 		}
-
-		d.Update(resultInputTooLarge, false)
-		return
 	}
 
-	d.Update(resultInputInvalid, false)
-}
-
-func (d *Data) Update(result string, alignRight bool) {
-	d.Result = result
-	d.AlignRight = alignRight
-	qml.Changed(d, &d.Result)
+	if count <= 4 {
+		return newResult(value, 32), nil
+	}
+	return newResult(value, 64), nil
 }
 
-func CreateWindow() error {
-	engine := qml.NewEngine()
+func Convert(text string) (*Result, error) {
+	str := strings.TrimSpace(text)
 
-	converter, err := engine.LoadFile("qrc:///qml/converter/Window.qml")
-	if err != nil {
-		return err
+	if str == "" {
+		return nil, nil
 	}
 
-	context := engine.Context()
-	context.SetVar("app", new(Data))
-
-	window := converter.CreateWindow(nil)
-	window.Show()
+	switch {
+	case matchHex.MatchString(str):
+		return parseUnsigned(str[2:], 16)
+	case matchBin.MatchString(str):
+		return parseUnsigned(str[2:], 2)
+	case matchOct.MatchString(str):
+		return parseUnsigned(str[2:], 8)
+	case matchDec.MatchString(str):
+		if !strings.HasPrefix(str, "-") {
+			return parseUnsigned(str, 10)
+		}
+		value, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return nil, ErrInputTooLarge
+		}
+		if value >= math.MinInt32 {
+			return newResult(uint64(value), 32), nil
+		}
+		return newResult(uint64(value), 64), nil
+	case matchBytes.MatchString(str), matchOrder.MatchString(str):
+		return parseBytes(str)
+	}
 
-	return nil
+	logging.Debug("Number not recognized", "input", str)
+	return nil, ErrInputInvalid
 }
@@ -0,0 +1,163 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Fogity/TS4Libs/consts"
+	"github.com/Fogity/TS4Libs/hash"
+	"github.com/Fogity/TS4Libs/keys"
+	"github.com/Fogity/TS4Tools/logging"
+)
+
+const (
+	formatS4      = "S4_%08X_%08X_%016X"
+	formatTriplet = "%08X:%08X:%016X"
+	formatS4pe    = "0x%08X-0x%08X-0x%016X"
+
+	unknownType = "Unknown"
+)
+
+var ErrKeyInvalid = errors.New("Invalid resource key")
+
+var (
+	matchS4      = regexp.MustCompile("^(?i:S4)_([0-9a-fA-F]{1,8})_([0-9a-fA-F]{1,8})_([0-9a-fA-F]{1,16})(\\..*)?$")
+	matchTriplet = regexp.MustCompile("^((?:0[xX])?[0-9a-fA-F]{1,8}):((?:0[xX])?[0-9a-fA-F]{1,8}):((?:0[xX])?[0-9a-fA-F]{1,16})$")
+	matchS4pe    = regexp.MustCompile("^0[xX]([0-9a-fA-F]{1,8})-0[xX]([0-9a-fA-F]{1,8})-0[xX]([0-9a-fA-F]{1,16})$")
+)
+
+var typeNames = map[uint32]string{
+	consts.ResourceTypeCasPart:      "CasPart",
+	consts.ResourceTypeTuningModule: "TuningModule",
+	0x220557DA:                      "StringTable",
+	0x62E94D38:                      "CombinedTuning",
+	0x545AC67A:                      "SimData",
+	0x3C1AF1F2:                      "CasPartThumbnail",
+	0x5B282D45:                      "BodyPartThumbnail",
+	0x3C2A8647:                      "BuyBuildThumbnail",
+	0xC0DB5AE7:                      "ObjectDefinition",
+	0x319E4F1D:                      "CatalogObject",
+	0x015A1849:                      "Geometry",
+	0x00B2D882:                      "DdsImage",
+	0x2F7D0004:                      "PngImage",
+}
+
+var tuningTypes = []string{
+	"achievement", "animation", "aspiration", "balloon", "broadcaster",
+	"buff", "business", "career", "club_interaction_group", "commodity",
+	"drama_node", "ensemble", "holiday_definition", "interaction",
+	"lot_decoration", "lot_tuning", "mood", "object", "pie_menu_category",
+	"posture", "recipe", "region", "relbit", "reward", "service_npc",
+	"sickness", "sim_filter", "situation", "situation_job", "snippet",
+	"spell", "statistic", "street", "topic", "trait", "tutorial", "venue",
+	"walk_by", "zone_director", "zone_modifier",
+}
+
+func init() {
+	for _, name := range tuningTypes {
+		typeNames[hash.Fnv32(name)] = fmt.Sprintf("Tuning (%v)", name)
+	}
+}
+
+type KeyResult struct {
+	Key                         keys.Key
+	Type, Group, Instance       string
+	TypeName, S4, Triplet, S4pe string
+}
+
+func TypeName(t uint32) string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return unknownType
+}
+
+func parseKeyPart(str string, bits int) (uint64, error) {
+	if len(str) > 2 && (str[:2] == "0x" || str[:2] == "0X") {
+		str = str[2:]
+	}
+	return strconv.ParseUint(str, 16, bits)
+}
+
+func ParseKey(text string) (keys.Key, error) {
+	str := strings.TrimSpace(text)
+
+	var parts []string
+	for _, match := range []*regexp.Regexp{matchS4, matchTriplet, matchS4pe} {
+		if m := match.FindStringSubmatch(str); m != nil {
+			parts = m[1:4]
+			break
+		}
+	}
+	if parts == nil {
+		return keys.Key{}, ErrKeyInvalid
+	}
+
+	t, err := parseKeyPart(parts[0], 32)
+	if err != nil {
+		return keys.Key{}, ErrKeyInvalid
+	}
+	g, err := parseKeyPart(parts[1], 32)
+	if err != nil {
+		return keys.Key{}, ErrKeyInvalid
+	}
+	i, err := parseKeyPart(parts[2], 64)
+	if err != nil {
+		return keys.Key{}, ErrKeyInvalid
+	}
+
+	var key keys.Key
+	key.Type = uint32(t)
+	key.Group = uint32(g)
+	key.Instance = i
+	return key, nil
+}
+
+func FormatKey(key keys.Key) *KeyResult {
+	return &KeyResult{
+		Key:      key,
+		Type:     fmt.Sprintf(formatHex32, key.Type),
+		Group:    fmt.Sprintf(formatHex32, key.Group),
+		Instance: fmt.Sprintf(formatHex64, key.Instance),
+		TypeName: TypeName(key.Type),
+		S4:       fmt.Sprintf(formatS4, key.Type, key.Group, key.Instance),
+		Triplet:  fmt.Sprintf(formatTriplet, key.Type, key.Group, key.Instance),
+		S4pe:     fmt.Sprintf(formatS4pe, key.Type, key.Group, key.Instance),
+	}
+}
+
+func ConvertKey(text string) (*KeyResult, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	key, err := ParseKey(text)
+	if err != nil {
+		logging.Debug("Resource key not recognized", "input", text, "error", err)
+		return nil, err
+	}
+
+	return FormatKey(key), nil
+}
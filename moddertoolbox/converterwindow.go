@@ -0,0 +1,142 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"github.com/Fogity/TS4Tools/moddertoolbox/converter"
+	"gopkg.in/qml.v1"
+)
+
+type Converter struct {
+	Information             string
+	Unsigned32, Signed32    string
+	Unsigned64, Signed64    string
+	Hex, Binary, Octal      string
+	LittleEndian, BigEndian string
+
+	KeyInformation                 string
+	KeyType, KeyGroup, KeyInstance string
+	KeyTypeName, S4, Triplet, S4pe string
+}
+
+func (d *Converter) ChangeText(text string) {
+	result, err := converter.Convert(text)
+	if err != nil {
+		d.Update(new(converter.Result), err.Error())
+		return
+	}
+
+	if result == nil {
+		d.Update(new(converter.Result), "")
+		return
+	}
+
+	d.Update(result, "")
+}
+
+func (d *Converter) Update(result *converter.Result, information string) {
+	d.Information = information
+	qml.Changed(d, &d.Information)
+
+	d.Unsigned32 = result.Unsigned32
+	qml.Changed(d, &d.Unsigned32)
+
+	d.Signed32 = result.Signed32
+	qml.Changed(d, &d.Signed32)
+
+	d.Unsigned64 = result.Unsigned64
+	qml.Changed(d, &d.Unsigned64)
+
+	d.Signed64 = result.Signed64
+	qml.Changed(d, &d.Signed64)
+
+	d.Hex = result.Hex
+	qml.Changed(d, &d.Hex)
+
+	d.Binary = result.Binary
+	qml.Changed(d, &d.Binary)
+
+	d.Octal = result.Octal
+	qml.Changed(d, &d.Octal)
+
+	d.LittleEndian = result.LittleEndian
+	qml.Changed(d, &d.LittleEndian)
+
+	d.BigEndian = result.BigEndian
+	qml.Changed(d, &d.BigEndian)
+}
+
+func (d *Converter) ChangeKeyText(text string) {
+	result, err := converter.ConvertKey(text)
+	if err != nil {
+		d.UpdateKey(new(converter.KeyResult), err.Error())
+		return
+	}
+
+	if result == nil {
+		d.UpdateKey(new(converter.KeyResult), "")
+		return
+	}
+
+	d.UpdateKey(result, "")
+}
+
+func (d *Converter) UpdateKey(result *converter.KeyResult, information string) {
+	d.KeyInformation = information
+	qml.Changed(d, &d.KeyInformation)
+
+	d.KeyType = result.Type
+	qml.Changed(d, &d.KeyType)
+
+	d.KeyGroup = result.Group
+	qml.Changed(d, &d.KeyGroup)
+
+	d.KeyInstance = result.Instance
+	qml.Changed(d, &d.KeyInstance)
+
+	d.KeyTypeName = result.TypeName
+	qml.Changed(d, &d.KeyTypeName)
+
+	d.S4 = result.S4
+	qml.Changed(d, &d.S4)
+
+	d.Triplet = result.Triplet
+	qml.Changed(d, &d.Triplet)
+
+	d.S4pe = result.S4pe
+	qml.Changed(d, &d.S4pe)
+}
+
+func createConverterWindow() error {
+	engine := qml.NewEngine()
+
+	tool, err := engine.LoadFile("qrc:///qml/converter/Window.qml")
+	if err != nil {
+		return err
+	}
+
+	context := engine.Context()
+	context.SetVar("app", new(Converter))
+
+	window := tool.CreateWindow(nil)
+	window.Show()
+
+	return nil
+}
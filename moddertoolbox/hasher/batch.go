@@ -0,0 +1,111 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package hasher
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Fogity/TS4Tools/logging"
+)
+
+const (
+	OutputTable = "table"
+	OutputCSV   = "csv"
+	OutputJSON  = "json"
+)
+
+var columns = []string{"Name", KindFnv24, KindFnv32, KindFnv32High, KindFnv64, KindFnv64High}
+
+type Row struct {
+	Name string
+	Hashes
+}
+
+func ReadNames(r io.Reader) ([]string, error) {
+	names := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, scanner.Err()
+}
+
+func Batch(names []string) []Row {
+	rows := make([]Row, len(names))
+	for i, name := range names {
+		rows[i] = Row{name, Calculate(name)}
+	}
+	logging.Debug("Hashed names", "count", len(rows))
+	return rows
+}
+
+func (r Row) fields(format string) []string {
+	return []string{
+		r.Name,
+		Format32(r.Fnv24, format),
+		Format32(r.Fnv32, format),
+		Format32(r.Fnv32High, format),
+		Format64(r.Fnv64, format),
+		Format64(r.Fnv64High, format),
+	}
+}
+
+func WriteBatch(w io.Writer, rows []Row, output, format string) error {
+	switch output {
+	case OutputTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "%v\n", strings.Join(columns, "\t"))
+		for _, r := range rows {
+			fmt.Fprintf(tw, "%v\n", strings.Join(r.fields(format), "\t"))
+		}
+		return tw.Flush()
+	case OutputCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(columns)
+		for _, r := range rows {
+			cw.Write(r.fields(format))
+		}
+		cw.Flush()
+		return cw.Error()
+	case OutputJSON:
+		objects := make([]map[string]string, len(rows))
+		for i, r := range rows {
+			object := make(map[string]string)
+			for j, field := range r.fields(format) {
+				object[columns[j]] = field
+			}
+			objects[i] = object
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		return encoder.Encode(objects)
+	}
+	return fmt.Errorf("unknown output %q", output)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package hasher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/logging"
+)
+
+const (
+	KindFnv24     = "FNV24"
+	KindFnv32     = "FNV32"
+	KindFnv32High = "FNV32High"
+	KindFnv64     = "FNV64"
+	KindFnv64High = "FNV64High"
+
+	dictionaryHeader = "# TS4Tools hash dictionary"
+)
+
+var (
+	ErrHashInvalid = errors.New("Invalid hash")
+
+	matchHex = regexp.MustCompile("^0x[0-9a-fA-F]{1,16}$")
+	matchDec = regexp.MustCompile("^[0-9]+$")
+)
+
+type Match struct {
+	Name, Kind string
+}
+
+type Dictionary struct {
+	names map[string]bool
+	index map[uint64][]Match
+}
+
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		names: make(map[string]bool),
+		index: make(map[uint64][]Match),
+	}
+}
+
+func (d *Dictionary) Add(name string) bool {
+	if name == "" || d.names[name] {
+		return false
+	}
+	d.names[name] = true
+	h := Calculate(name)
+	d.insert(uint64(h.Fnv24), Match{name, KindFnv24})
+	d.insert(uint64(h.Fnv32), Match{name, KindFnv32})
+	d.insert(uint64(h.Fnv32High), Match{name, KindFnv32High})
+	d.insert(h.Fnv64, Match{name, KindFnv64})
+	d.insert(h.Fnv64High, Match{name, KindFnv64High})
+	return true
+}
+
+func (d *Dictionary) insert(value uint64, match Match) {
+	for _, m := range d.index[value] {
+		if m == match {
+			return
+		}
+	}
+	d.index[value] = append(d.index[value], match)
+}
+
+func (d *Dictionary) Len() int {
+	return len(d.names)
+}
+
+func (d *Dictionary) Names() []string {
+	names := make([]string, 0, len(d.names))
+	for name := range d.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *Dictionary) Lookup(value uint64) []Match {
+	matches := append([]Match(nil), d.index[value]...)
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Kind != matches[j].Kind {
+			return matches[i].Kind < matches[j].Kind
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
+
+func ParseHash(text string) (uint64, error) {
+	str := strings.TrimSpace(text)
+
+	var value uint64
+	var err error
+	switch {
+	case matchHex.MatchString(str):
+		value, err = strconv.ParseUint(str[2:], 16, 64)
+	case matchDec.MatchString(str):
+		value, err = strconv.ParseUint(str, 10, 64)
+	default:
+		return 0, ErrHashInvalid
+	}
+	if err != nil {
+		return 0, ErrHashInvalid
+	}
+
+	return value, nil
+}
+
+func LoadDictionary(path string) (*Dictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	d := NewDictionary()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		d.Add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	logging.Info("Loaded hash dictionary", "path", path, "names", d.Len())
+	return d, nil
+}
+
+func (d *Dictionary) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	fmt.Fprintln(w, dictionaryHeader)
+	for _, name := range d.Names() {
+		fmt.Fprintln(w, name)
+	}
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	logging.Info("Saved hash dictionary", "path", path, "names", d.Len())
+	return nil
+}
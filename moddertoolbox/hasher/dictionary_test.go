@@ -0,0 +1,52 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package hasher
+
+import "testing"
+
+func TestParseHash(t *testing.T) {
+	valid := []struct {
+		text  string
+		value uint64
+	}{
+		{"123", 123},
+		{"0123", 123},
+		{"0999", 999},
+		{"0", 0},
+		{" 42 ", 42},
+		{"0x1F", 0x1F},
+		{"0xFFFFFFFFFFFFFFFF", 0xFFFFFFFFFFFFFFFF},
+		{"18446744073709551615", 18446744073709551615},
+	}
+	for _, c := range valid {
+		value, err := ParseHash(c.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", c.text, err)
+		} else if value != c.value {
+			t.Errorf("%q: got %v, want %v", c.text, value, c.value)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "0x", "0xG1", "-1", "18446744073709551616", "99999999999999999999", "0x10000000000000000"} {
+		if _, err := ParseHash(text); err != ErrHashInvalid {
+			t.Errorf("%q: got %v, want ErrHashInvalid", text, err)
+		}
+	}
+}
@@ -23,12 +23,11 @@ import (
 	"fmt"
 
 	"github.com/Fogity/TS4Libs/hash"
-	"gopkg.in/qml.v1"
 )
 
 const (
-	numberFormatHex = "hex"
-	numberFormatDec = "dec"
+	NumberFormatHex = "hex"
+	NumberFormatDec = "dec"
 
 	formatHex32 = "0x%08X"
 	formatHex64 = "0x%016X"
@@ -36,70 +35,31 @@ const (
 	formatDec64 = "%v"
 )
 
-type Data struct {
-	Fnv24, Fnv32, Fnv32High, Fnv64, Fnv64High string
-	Text, Format                              string
+type Hashes struct {
+	Fnv24, Fnv32, Fnv32High uint32
+	Fnv64, Fnv64High        uint64
 }
 
-func (d *Data) ChangeText(text string) {
-	d.Text = text
-	d.Calculate()
-}
-
-func (d *Data) ChangeFormat(format string) {
-	d.Format = format
-	d.Calculate()
+func Calculate(text string) Hashes {
+	return Hashes{
+		Fnv24:     hash.Fnv24(text),
+		Fnv32:     hash.Fnv32(text),
+		Fnv32High: hash.Fnv32HighBit(text),
+		Fnv64:     hash.Fnv64(text),
+		Fnv64High: hash.Fnv64HighBit(text),
+	}
 }
 
-func (d *Data) Calculate() {
-	var f32, f64 string
-
-	switch d.Format {
-	case numberFormatHex:
-		f32 = formatHex32
-		f64 = formatHex64
-	default:
-		f32 = formatDec32
-		f64 = formatDec64
+func Format32(value uint32, format string) string {
+	if format == NumberFormatHex {
+		return fmt.Sprintf(formatHex32, value)
 	}
-
-	ui32 := hash.Fnv24(d.Text)
-	d.Fnv24 = fmt.Sprintf(f32, ui32)
-	qml.Changed(d, &d.Fnv24)
-
-	ui32 = hash.Fnv32(d.Text)
-	d.Fnv32 = fmt.Sprintf(f32, ui32)
-	qml.Changed(d, &d.Fnv32)
-
-	ui32 = hash.Fnv32HighBit(d.Text)
-	d.Fnv32High = fmt.Sprintf(f32, ui32)
-	qml.Changed(d, &d.Fnv32High)
-
-	ui64 := hash.Fnv64(d.Text)
-	d.Fnv64 = fmt.Sprintf(f64, ui64)
-	qml.Changed(d, &d.Fnv64)
-
-	ui64 = hash.Fnv64HighBit(d.Text)
-	d.Fnv64High = fmt.Sprintf(f64, ui64)
-	qml.Changed(d, &d.Fnv64High)
+	return fmt.Sprintf(formatDec32, value)
 }
 
-func CreateWindow() error {
-	engine := qml.NewEngine()
-
-	hasher, err := engine.LoadFile("qrc:///qml/hasher/Window.qml")
-	if err != nil {
-		return err
+func Format64(value uint64, format string) string {
+	if format == NumberFormatHex {
+		return fmt.Sprintf(formatHex64, value)
 	}
-
-	context := engine.Context()
-
-	d := new(Data)
-	d.ChangeFormat(numberFormatHex)
-	context.SetVar("app", d)
-
-	window := hasher.CreateWindow(nil)
-	window.Show()
-
-	return nil
+	return fmt.Sprintf(formatDec64, value)
 }
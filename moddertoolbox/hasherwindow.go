@@ -0,0 +1,169 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/moddertoolbox/hasher"
+	"gopkg.in/qml.v1"
+)
+
+type Hasher struct {
+	Fnv24, Fnv32, Fnv32High, Fnv64, Fnv64High string
+	Text, Format                              string
+	BatchText, BatchOutput, Batch             string
+	DictionaryFile, LookupText, Lookup        string
+
+	dictionary     *hasher.Dictionary
+	dictionaryFile string
+}
+
+func (d *Hasher) ChangeBatchText(text string) {
+	d.BatchText = text
+	d.CalculateBatch()
+}
+
+func (d *Hasher) ChangeBatchOutput(output string) {
+	d.BatchOutput = output
+	d.CalculateBatch()
+}
+
+func (d *Hasher) ChangeText(text string) {
+	d.Text = text
+	d.Calculate()
+}
+
+func (d *Hasher) ChangeFormat(format string) {
+	d.Format = format
+	d.Calculate()
+}
+
+func (d *Hasher) Calculate() {
+	h := hasher.Calculate(d.Text)
+
+	d.Fnv24 = hasher.Format32(h.Fnv24, d.Format)
+	qml.Changed(d, &d.Fnv24)
+
+	d.Fnv32 = hasher.Format32(h.Fnv32, d.Format)
+	qml.Changed(d, &d.Fnv32)
+
+	d.Fnv32High = hasher.Format32(h.Fnv32High, d.Format)
+	qml.Changed(d, &d.Fnv32High)
+
+	d.Fnv64 = hasher.Format64(h.Fnv64, d.Format)
+	qml.Changed(d, &d.Fnv64)
+
+	d.Fnv64High = hasher.Format64(h.Fnv64High, d.Format)
+	qml.Changed(d, &d.Fnv64High)
+
+	d.CalculateBatch()
+}
+
+func (d *Hasher) CalculateBatch() {
+	names, err := hasher.ReadNames(strings.NewReader(d.BatchText))
+	if err != nil {
+		d.Batch = err.Error()
+		qml.Changed(d, &d.Batch)
+		return
+	}
+
+	var buffer bytes.Buffer
+	if len(names) > 0 {
+		err = hasher.WriteBatch(&buffer, hasher.Batch(names), d.BatchOutput, d.Format)
+		if err != nil {
+			buffer.Reset()
+			buffer.WriteString(err.Error())
+		}
+	}
+
+	d.Batch = buffer.String()
+	qml.Changed(d, &d.Batch)
+}
+
+func (d *Hasher) ChangeLookupText(text string) {
+	d.LookupText = text
+	d.CalculateLookup()
+}
+
+func (d *Hasher) CalculateLookup() {
+	d.Lookup = d.lookup()
+	qml.Changed(d, &d.Lookup)
+}
+
+func (d *Hasher) lookup() string {
+	if strings.TrimSpace(d.LookupText) == "" {
+		return ""
+	}
+
+	if d.DictionaryFile == "" {
+		return "A Dictionary must be specified."
+	}
+
+	if d.dictionary == nil || d.dictionaryFile != d.DictionaryFile {
+		dictionary, err := hasher.LoadDictionary(trimPath(d.DictionaryFile))
+		if err != nil {
+			logging.Error("Hash dictionary could not be loaded", "path", d.DictionaryFile, "error", err)
+			return err.Error()
+		}
+		d.dictionary = dictionary
+		d.dictionaryFile = d.DictionaryFile
+	}
+
+	value, err := hasher.ParseHash(d.LookupText)
+	if err != nil {
+		return err.Error()
+	}
+
+	matches := d.dictionary.Lookup(value)
+	if len(matches) == 0 {
+		return "No match"
+	}
+
+	lines := make([]string, len(matches))
+	for i, m := range matches {
+		lines[i] = fmt.Sprintf("%v (%v)", m.Name, m.Kind)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func createHasherWindow() error {
+	engine := qml.NewEngine()
+
+	tool, err := engine.LoadFile("qrc:///qml/hasher/Window.qml")
+	if err != nil {
+		return err
+	}
+
+	context := engine.Context()
+
+	d := new(Hasher)
+	d.BatchOutput = hasher.OutputTable
+	d.ChangeFormat(hasher.NumberFormatHex)
+	context.SetVar("app", d)
+
+	window := tool.CreateWindow(nil)
+	window.Show()
+
+	return nil
+}
@@ -3,10 +3,15 @@ import QtQuick.Controls 1.3
 
 ApplicationWindow {
 	title: "Modder Toolbox"
-	width: 200
-	height: 100
+	width: 500
+	height: 300
 
 	Flow {
+		id: tools
+		anchors.top: parent.top
+		anchors.left: parent.left
+		anchors.right: parent.right
+
 		Button {
 			text: "Hasher"
 			onClicked: { app.create("hasher") }
@@ -17,4 +22,13 @@ ApplicationWindow {
 			onClicked: { app.create("converter") }
 		}
 	}
+
+	TextArea {
+		anchors.top: tools.bottom
+		anchors.left: parent.left
+		anchors.right: parent.right
+		anchors.bottom: parent.bottom
+		readOnly: true
+		text: app.log
+	}
 }
@@ -4,6 +4,8 @@ import QtQuick.Controls 1.3
 ApplicationWindow {
 	property real windowMargin: 8
 	property real windowSpacing: 4
+	property int numberFieldWidth: 200
+	property int wideFieldWidth: 2 * numberFieldWidth + windowSpacing
 
 	title: "Converter"
 	width: body.width + 2 * windowMargin + 2
@@ -20,25 +22,254 @@ ApplicationWindow {
 		anchors.left: parent.left
 		anchors.margins: windowMargin
 
-		Label { text: "Enter number to convert between\nhexadecimal and decimal:" }
+		Row {
+			spacing: windowSpacing
 
-		TextField {
-			width: parent.width
-			onTextChanged: { app.changeText(text) }
+			Label {
+				id: modeLabel
+				text: "Convert"
+			}
+
+			ExclusiveGroup { id: mode }
+
+			RadioButton {
+				id: numberModeButton
+				text: "Number"
+				checked: true
+				exclusiveGroup: mode
+				anchors.baseline: modeLabel.baseline
+			}
+
+			RadioButton {
+				text: "Resource Key"
+				exclusiveGroup: mode
+				anchors.baseline: modeLabel.baseline
+			}
 		}
 
-		Label { text: "Result:" }
+		Column {
+			spacing: windowSpacing
+			visible: numberModeButton.checked
+
+			Label { text: "Enter number to convert, as decimal, 0x hexadecimal,\n0b binary, 0o octal or bytes (e.g. FB FF FF FF, be:FF FF FF FB):" }
+
+			TextField {
+				width: parent.width
+				onTextChanged: { app.changeText(text) }
+			}
+
+			Label { text: app.information }
 
-		TextField {
-			text: app.result
-			width: parent.width
-			readOnly: true
-			onTextChanged: {
-				if (app.alignRight) {
-					horizontalAlignment = TextInput.AlignRight
-				} else {
-					horizontalAlignment = TextInput.AlignLeft
+			Grid {
+				columns: 2
+				spacing: windowSpacing
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Unsigned 32" }
+
+					TextField {
+						text: app.unsigned32
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Signed 32" }
+
+					TextField {
+						text: app.signed32
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
 				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Unsigned 64" }
+
+					TextField {
+						text: app.unsigned64
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Signed 64" }
+
+					TextField {
+						text: app.signed64
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Hexadecimal" }
+
+					TextField {
+						text: app.hex
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Octal" }
+
+					TextField {
+						text: app.octal
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Little Endian Bytes" }
+
+					TextField {
+						text: app.littleEndian
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Big Endian Bytes" }
+
+					TextField {
+						text: app.bigEndian
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+			}
+
+			Label { text: "Binary" }
+
+			TextField {
+				text: app.binary
+				width: wideFieldWidth
+				readOnly: true
+				horizontalAlignment: TextInput.AlignRight
+			}
+		}
+
+		Column {
+			spacing: windowSpacing
+			visible: !numberModeButton.checked
+
+			Label { text: "Enter resource key as S4_TTTTTTTT_GGGGGGGG_IIIIIIIIIIIIIIII,\nTTTTTTTT:GGGGGGGG:IIIIIIIIIIIIIIII or 0xTTTTTTTT-0xGGGGGGGG-0xIIIIIIIIIIIIIIII:" }
+
+			TextField {
+				width: wideFieldWidth
+				onTextChanged: { app.changeKeyText(text) }
+			}
+
+			Label { text: app.keyInformation }
+
+			Grid {
+				columns: 2
+				spacing: windowSpacing
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Type" }
+
+					TextField {
+						text: app.keyType
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Type Name" }
+
+					TextField {
+						text: app.keyTypeName
+						width: numberFieldWidth
+						readOnly: true
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Group" }
+
+					TextField {
+						text: app.keyGroup
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+
+				Column {
+					spacing: windowSpacing
+
+					Label { text: "Instance" }
+
+					TextField {
+						text: app.keyInstance
+						width: numberFieldWidth
+						readOnly: true
+						horizontalAlignment: TextInput.AlignRight
+					}
+				}
+			}
+
+			Label { text: "S4 Name" }
+
+			TextField {
+				text: app.s4
+				width: wideFieldWidth
+				readOnly: true
+			}
+
+			Label { text: "Triplet" }
+
+			TextField {
+				text: app.triplet
+				width: wideFieldWidth
+				readOnly: true
+			}
+
+			Label { text: "s4pe" }
+
+			TextField {
+				text: app.s4pe
+				width: wideFieldWidth
+				readOnly: true
 			}
 		}
 	}
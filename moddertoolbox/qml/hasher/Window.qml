@@ -1,10 +1,27 @@
 import QtQuick 2.4
 import QtQuick.Controls 1.3
+import QtQuick.Dialogs 1.2
 
 ApplicationWindow {
+	FileDialog {
+		id: dictionaryDialog
+		title: "Please choose a dictionary"
+		nameFilters: [ "Text files (*.txt)", "All files (*)" ]
+		onAccepted: { app.calculateLookup() }
+	}
+
+	Binding {
+		target: app
+		property: "dictionaryFile"
+		value: dictionaryDialog.fileUrl
+	}
+
 	property real windowMargin: 8
 	property real windowSpacing: 4
 	property int hashFieldWidth: 160
+	property int batchWidth: 640
+	property int batchHeight: 160
+	property int lookupHeight: 80
 
 	title: "Hasher"
 	width: body.width + 2 * windowMargin + 2
@@ -133,5 +150,98 @@ ApplicationWindow {
 				}
 			}
 		}
+
+		Label { text: "Paste names to hash, one per line:" }
+
+		TextArea {
+			width: batchWidth
+			height: batchHeight
+			onTextChanged: { app.changeBatchText(text) }
+		}
+
+		Row {
+			spacing: windowSpacing
+
+			Label {
+				id: outputLabel
+				text: "Display batch as"
+			}
+
+			ExclusiveGroup { id: batchOutput }
+
+			RadioButton {
+				text: "Table"
+				checked: true
+				exclusiveGroup: batchOutput
+				anchors.baseline: outputLabel.baseline
+				onCheckedChanged: {
+					if (checked) {
+						app.changeBatchOutput("table")
+					}
+				}
+			}
+
+			RadioButton {
+				text: "CSV"
+				exclusiveGroup: batchOutput
+				anchors.baseline: outputLabel.baseline
+				onCheckedChanged: {
+					if (checked) {
+						app.changeBatchOutput("csv")
+					}
+				}
+			}
+
+			RadioButton {
+				text: "JSON"
+				exclusiveGroup: batchOutput
+				anchors.baseline: outputLabel.baseline
+				onCheckedChanged: {
+					if (checked) {
+						app.changeBatchOutput("json")
+					}
+				}
+			}
+		}
+
+		TextArea {
+			text: app.batch
+			width: batchWidth
+			height: batchHeight
+			readOnly: true
+			font.family: "monospace"
+		}
+
+		Label { text: "Dictionary:" }
+
+		Row {
+			spacing: windowSpacing
+
+			TextField {
+				text: dictionaryDialog.fileUrl
+				width: batchWidth - browseButton.width - windowSpacing
+				enabled: false
+			}
+
+			Button {
+				id: browseButton
+				text: "Browse"
+				onClicked: { dictionaryDialog.open() }
+			}
+		}
+
+		Label { text: "Hash to look up (hexadecimal or decimal):" }
+
+		TextField {
+			width: batchWidth
+			onTextChanged: { app.changeLookupText(text) }
+		}
+
+		TextArea {
+			text: app.lookup
+			width: batchWidth
+			height: lookupHeight
+			readOnly: true
+		}
 	}
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package failures
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/Fogity/TS4Tools/logging"
+)
+
+type Policy string
+
+const (
+	PolicyKeepGoing Policy = "keep-going"
+	PolicyStrict    Policy = "strict"
+)
+
+const ReportFile = "failures.json"
+
+type Failure struct {
+	Key     string `json:"key,omitempty"`
+	Package string `json:"package"`
+	Error   string `json:"error"`
+}
+
+func FormatKey(t, g uint32, i uint64) string {
+	return fmt.Sprintf("S4_%08X_%08X_%016X", t, g, i)
+}
+
+type Error struct {
+	Failure
+}
+
+func (e *Error) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%v: %v", e.Package, e.Failure.Error)
+	}
+	return fmt.Sprintf("%v in %v: %v", e.Key, e.Package, e.Failure.Error)
+}
+
+type Report struct {
+	policy   Policy
+	mutex    sync.Mutex
+	failures []Failure
+}
+
+func NewReport(policy Policy) *Report {
+	return &Report{policy: policy, failures: make([]Failure, 0)}
+}
+
+func (r *Report) Add(key, pack string, err error) error {
+	f := Failure{key, pack, err.Error()}
+	logging.Warn("Resource failed", "key", key, "package", pack, "error", err)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.failures = append(r.failures, f)
+	if r.policy == PolicyStrict {
+		return &Error{f}
+	}
+	return nil
+}
+
+func (r *Report) Failures() []Failure {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Failure(nil), r.failures...)
+}
+
+func (r *Report) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.failures)
+}
+
+func (r *Report) Summary() string {
+	n := r.Len()
+	switch n {
+	case 0:
+		return "no failures"
+	case 1:
+		return fmt.Sprintf("1 resource failed, see %v", ReportFile)
+	}
+	return fmt.Sprintf("%v resources failed, see %v", n, ReportFile)
+}
+
+func (r *Report) Write(folder string) error {
+	path := fmt.Sprintf("%v/%v", folder, ReportFile)
+	failures := r.Failures()
+	if len(failures) == 0 {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(failures); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
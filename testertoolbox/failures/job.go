@@ -0,0 +1,132 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package failures
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fogity/TS4Libs/keys"
+	"github.com/Fogity/TS4Tools/logging"
+)
+
+type Phase string
+
+type Event struct {
+	Phase                    Phase
+	Packages, Written, Total int
+	Writing                  bool
+	Unit                     string
+}
+
+func (e Event) String() string {
+	if e.Writing {
+		return fmt.Sprintf("%v, %v of %v %v written...", e.Phase, e.Written, e.Total, e.Unit)
+	}
+	if e.Packages > 0 {
+		return fmt.Sprintf("%v, %v packages read...", e.Phase, e.Packages)
+	}
+	return fmt.Sprintf("%v...", e.Phase)
+}
+
+func (e Event) Fraction() float64 {
+	if !e.Writing || e.Total == 0 {
+		return 0
+	}
+	return float64(e.Written) / float64(e.Total)
+}
+
+func Strict(strict bool) Policy {
+	if strict {
+		return PolicyStrict
+	}
+	return PolicyKeepGoing
+}
+
+type Job struct {
+	ctx      context.Context
+	progress func(Event)
+	event    Event
+	report   *Report
+}
+
+func NewJob(ctx context.Context, policy Policy, unit string, progress func(Event)) *Job {
+	if progress == nil {
+		progress = func(Event) {}
+	}
+	return &Job{ctx, progress, Event{Unit: unit}, NewReport(policy)}
+}
+
+func (j *Job) Context() context.Context {
+	return j.ctx
+}
+
+func (j *Job) Err() error {
+	return j.ctx.Err()
+}
+
+func (j *Job) Report() *Report {
+	return j.report
+}
+
+func (j *Job) Phase(phase Phase) error {
+	logging.Info(string(phase))
+	j.event.Phase = phase
+	j.event.Packages = 0
+	j.event.Writing = false
+	j.progress(j.event)
+	return j.ctx.Err()
+}
+
+func (j *Job) Writing(phase Phase, total int) error {
+	logging.Info(string(phase), "total", total)
+	j.event.Phase = phase
+	j.event.Writing = true
+	j.event.Written = 0
+	j.event.Total = total
+	j.progress(j.event)
+	return j.ctx.Err()
+}
+
+func (j *Job) Scanned() error {
+	j.event.Packages++
+	j.progress(j.event)
+	return j.ctx.Err()
+}
+
+func (j *Job) Written() error {
+	j.event.Written++
+	j.progress(j.event)
+	return j.ctx.Err()
+}
+
+func (j *Job) Finish(folder string, err error) error {
+	if werr := j.report.Write(folder); werr != nil {
+		if err == nil {
+			return werr
+		}
+		logging.Warn("Failure report could not be written", "error", werr)
+	}
+	return err
+}
+
+func (j *Job) Fail(k keys.Key, pack string, err error) error {
+	return j.report.Add(FormatKey(k.Type, k.Group, k.Instance), pack, err)
+}
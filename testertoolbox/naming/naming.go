@@ -0,0 +1,289 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrTemplateEmpty      = errors.New("A naming template must be specified.")
+	ErrTemplateUnbalanced = errors.New("Naming template has an unbalanced brace.")
+)
+
+const (
+	Type     = "type"
+	TypeId   = "typeid"
+	Group    = "group"
+	GroupDir = "groupdir"
+	Instance = "instance"
+	Key      = "key"
+	Name     = "name"
+	Class    = "class"
+	Pack     = "pack"
+	Tag      = "tag"
+)
+
+var Placeholders = []string{Type, TypeId, Group, GroupDir, Instance, Key, Name, Class, Pack, Tag}
+
+type Layout struct {
+	Name, Template string
+}
+
+var TuningLayouts = []Layout{
+	{"default", "{groupdir}/{type}/{key}"},
+	{"readable", "{type}/{name}"},
+	{"pack", "{pack}/{type}/{name}"},
+	{"s4pe", "{key}%%+{tag}"},
+}
+
+var ThumbnailLayouts = []Layout{
+	{"default", "{name}_{group:x}"},
+	{"readable", "{name}"},
+	{"pack", "{pack}/{name}_{group:x}"},
+	{"s4pe", "{key}%%+{tag}"},
+}
+
+func Lookup(layouts []Layout, text string) string {
+	for _, l := range layouts {
+		if l.Name == text {
+			return l.Template
+		}
+	}
+	return text
+}
+
+func LayoutNames(layouts []Layout) []string {
+	names := make([]string, len(layouts))
+	for i, l := range layouts {
+		names[i] = l.Name
+	}
+	return names
+}
+
+type Fields struct {
+	Type, Group             uint32
+	Instance                uint64
+	Kind, Name, Class, Pack string
+	GroupDir, Tag           string
+}
+
+var numberFormat = regexp.MustCompile(`^0?[0-9]*[xXd]$`)
+
+func (f *Fields) value(placeholder, format string) string {
+	number := func(n uint64, fallback string) string {
+		if format != "" {
+			fallback = "%" + format
+		}
+		return fmt.Sprintf(fallback, n)
+	}
+
+	switch placeholder {
+	case Type:
+		if f.Kind != "" {
+			return f.Kind
+		}
+		return number(uint64(f.Type), "%08X")
+	case TypeId:
+		return number(uint64(f.Type), "%08X")
+	case Group:
+		return number(uint64(f.Group), "%08X")
+	case GroupDir:
+		if f.GroupDir != "" {
+			return f.GroupDir
+		}
+		return number(uint64(f.Group), "%08x")
+	case Instance:
+		return number(f.Instance, "%016X")
+	case Key:
+		return fmt.Sprintf("S4_%08X_%08X_%016X", f.Type, f.Group, f.Instance)
+	case Name:
+		return f.Name
+	case Class:
+		return f.Class
+	case Pack:
+		return f.Pack
+	case Tag:
+		return f.Tag
+	}
+	return ""
+}
+
+type part struct {
+	literal, placeholder, format string
+}
+
+type Template struct {
+	text  string
+	parts []part
+}
+
+func Parse(text string) (*Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrTemplateEmpty
+	}
+
+	t := &Template{text: text}
+	rest := text
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		end := strings.IndexByte(rest, '}')
+		if start < 0 {
+			if end >= 0 {
+				return nil, ErrTemplateUnbalanced
+			}
+			t.parts = append(t.parts, part{literal: rest})
+			break
+		}
+		if end < start {
+			return nil, ErrTemplateUnbalanced
+		}
+		if start > 0 {
+			t.parts = append(t.parts, part{literal: rest[:start]})
+		}
+
+		placeholder := rest[start+1 : end]
+		format := ""
+		if i := strings.IndexByte(placeholder, ':'); i >= 0 {
+			placeholder, format = placeholder[:i], placeholder[i+1:]
+		}
+		if !isPlaceholder(placeholder) {
+			return nil, fmt.Errorf("Unknown naming placeholder {%v}, expecting one of %v.", placeholder, strings.Join(Placeholders, ", "))
+		}
+		if format != "" && !numberFormat.MatchString(format) {
+			return nil, fmt.Errorf("Invalid number format %v for {%v}.", format, placeholder)
+		}
+		t.parts = append(t.parts, part{placeholder: placeholder, format: format})
+		rest = rest[end+1:]
+	}
+
+	return t, nil
+}
+
+func isPlaceholder(name string) bool {
+	for _, p := range Placeholders {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Template) String() string {
+	return t.text
+}
+
+func (t *Template) Uses(placeholder string) bool {
+	for _, p := range t.parts {
+		if p.placeholder == placeholder {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Template) Execute(f Fields) string {
+	var b strings.Builder
+	for _, p := range t.parts {
+		if p.placeholder == "" {
+			b.WriteString(p.literal)
+			continue
+		}
+		b.WriteString(strings.NewReplacer("/", "_", "\\", "_").Replace(f.value(p.placeholder, p.format)))
+	}
+
+	segments := strings.Split(b.String(), "/")
+	clean := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		clean = append(clean, Sanitize(s))
+	}
+	if len(clean) == 0 {
+		return "_"
+	}
+	return strings.Join(clean, "/")
+}
+
+var reserved = regexp.MustCompile(`(?i)^(con|prn|aux|nul|com[0-9]|lpt[0-9])(\..*)?$`)
+
+func Sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(name, ". ")
+	if name == "" || name == ".." {
+		return "_"
+	}
+	if reserved.MatchString(name) {
+		return "_" + name
+	}
+	return name
+}
+
+type Namer struct {
+	template  *Template
+	extension string
+	paths     map[string]string
+	ids       map[string]string
+	renamed   int
+}
+
+func NewNamer(template *Template, extension string) *Namer {
+	return &Namer{template, extension, make(map[string]string), make(map[string]string), 0}
+}
+
+func (n *Namer) Path(id string, f Fields) string {
+	if p, ok := n.ids[id]; ok {
+		return p
+	}
+
+	base := n.template.Execute(f)
+	p := base + n.extension
+	for i := 2; ; i++ {
+		owner, taken := n.paths[strings.ToLower(p)]
+		if !taken || owner == id {
+			break
+		}
+		p = fmt.Sprintf("%v_%v%v", base, i, n.extension)
+	}
+	if p != base+n.extension {
+		n.renamed++
+	}
+
+	n.paths[strings.ToLower(p)] = id
+	n.ids[id] = p
+	return p
+}
+
+func (n *Namer) Uses(placeholder string) bool {
+	return n.template.Uses(placeholder)
+}
+
+func (n *Namer) Collisions() int {
+	return n.renamed
+}
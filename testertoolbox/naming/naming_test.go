@@ -0,0 +1,53 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package naming
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDefaultTuningLayoutKeepsGroupDir(t *testing.T) {
+	template, err := Parse(Lookup(TuningLayouts, "default"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{"80000000", "62e94d38", "00000000"} {
+		var g uint32
+		fmt.Sscan(dir, &g)
+		f := Fields{Type: 0x6017E896, Group: g, Instance: 0x1234, Kind: "buff", GroupDir: dir, Tag: "XML"}
+		want := fmt.Sprintf("%v/buff/S4_6017E896_%08X_0000000000001234", dir, g)
+		if got := template.Execute(f); got != want {
+			t.Errorf("group dir %v: got %v, want %v", dir, got, want)
+		}
+	}
+}
+
+func TestGroupDirFallsBackToGroup(t *testing.T) {
+	template, err := Parse("{groupdir}/{key}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := Fields{Type: 1, Group: 0x80000000, Instance: 2}
+	if got, want := template.Execute(f), "80000000/S4_00000001_80000000_0000000000000002"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -3,10 +3,15 @@ import QtQuick.Controls 1.3
 
 ApplicationWindow {
 	title: "Tester Toolbox"
-	width: 200
-	height: 100
+	width: 500
+	height: 300
 
 	Flow {
+		id: tools
+		anchors.top: parent.top
+		anchors.left: parent.left
+		anchors.right: parent.right
+
 		Button {
 			text: "Thumbnail Extractor"
 			onClicked: { app.create("thumbextractor") }
@@ -16,5 +21,24 @@ ApplicationWindow {
 			text: "Tuning Extractor"
 			onClicked: { app.create("tuningextractor") }
 		}
+
+		Button {
+			text: "Tuning Search"
+			onClicked: { app.create("tuningsearch") }
+		}
+
+		Button {
+			text: "Tuning References"
+			onClicked: { app.create("tuningrefs") }
+		}
+	}
+
+	TextArea {
+		anchors.top: tools.bottom
+		anchors.left: parent.left
+		anchors.right: parent.right
+		anchors.bottom: parent.bottom
+		readOnly: true
+		text: app.log
 	}
 }
@@ -27,6 +27,24 @@ ApplicationWindow {
 		value: thumbnailDialog.fileUrl
 	}
 
+	FileDialog {
+		id: gameDirDialog
+		title: "Please choose a directory"
+		selectFolder: true
+	}
+
+	Binding {
+		target: app
+		property: "gameDir"
+		value: gameDirDialog.fileUrl
+	}
+
+	Binding {
+		target: app
+		property: "fromGame"
+		value: fromGame.checked
+	}
+
 	FileDialog {
 		id: exportDirDialog
 		title: "Please choose a directory"
@@ -39,6 +57,42 @@ ApplicationWindow {
 		value: exportDirDialog.fileUrl
 	}
 
+	Binding {
+		target: app
+		property: "naming"
+		value: naming.editText
+	}
+
+	Binding {
+		target: app
+		property: "instance"
+		value: instance.checked
+	}
+
+	Binding {
+		target: app
+		property: "index"
+		value: index.currentText
+	}
+
+	Binding {
+		target: app
+		property: "anyGroup"
+		value: anyGroup.checked
+	}
+
+	Binding {
+		target: app
+		property: "objects"
+		value: objects.checked
+	}
+
+	Binding {
+		target: app
+		property: "strict"
+		value: strict.checked
+	}
+
 	property real windowMargin: 8
 	property real windowSpacing: 4
 	property real fileNameWidth: 250
@@ -58,10 +112,40 @@ ApplicationWindow {
 		anchors.left: parent.left
 		anchors.margins: windowMargin
 
-		Label { text: "Cas Part Package:" }
+		CheckBox {
+			id: fromGame
+			text: "Extract every pack of a game installation"
+		}
+
+		Label {
+			text: "Game Directory:"
+			visible: fromGame.checked
+		}
 
 		Row {
 			spacing: windowSpacing
+			visible: fromGame.checked
+
+			TextField {
+				text: gameDirDialog.fileUrl
+				width: fileNameWidth
+				enabled: false
+			}
+
+			Button {
+				text: "Browse"
+				onClicked: { gameDirDialog.open() }
+			}
+		}
+
+		Label {
+			text: "Cas Part Package:"
+			visible: !fromGame.checked
+		}
+
+		Row {
+			spacing: windowSpacing
+			visible: !fromGame.checked
 
 			TextField {
 				text: casPartDialog.fileUrl
@@ -75,10 +159,14 @@ ApplicationWindow {
 			}
 		}
 
-		Label { text: "Thumbnail Package:" }
+		Label {
+			text: "Thumbnail Package:"
+			visible: !fromGame.checked
+		}
 
 		Row {
 			spacing: windowSpacing
+			visible: !fromGame.checked
 
 			TextField {
 				text: thumbnailDialog.fileUrl
@@ -109,12 +197,96 @@ ApplicationWindow {
 			}
 		}
 
-		Button {
-			text: "Extract"
+		Label { text: "File Naming (layout or template):" }
+
+		ComboBox {
+			id: naming
+			editable: true
+			width: fileNameWidth
+			model: app.namingList.split(",")
+		}
+
+		CheckBox {
+			id: instance
+			text: "Include the instance id in file names"
+		}
+
+		Label { text: "Index Format:" }
+
+		ComboBox {
+			id: index
+			model: app.indexList.split(",")
+		}
+
+		Label { text: "Thumbnail Types (none checked extracts the usual type for cas parts and objects):" }
+
+		Row {
+			spacing: windowSpacing
+
+			Repeater {
+				model: app.typeList.split(",")
+
+				CheckBox {
+					text: modelData
+					onCheckedChanged: { app.selectType(modelData, checked) }
+				}
+			}
+		}
+
+		Label { text: "Thumbnail Groups (none checked extracts cas part portraits):" }
+
+		Row {
+			spacing: windowSpacing
+
+			Repeater {
+				model: app.groupList.split(",")
+
+				CheckBox {
+					text: modelData
+					enabled: !anyGroup.checked
+					onCheckedChanged: { app.selectGroup(modelData, checked) }
+				}
+			}
+
+			CheckBox {
+				id: anyGroup
+				text: "Any group"
+			}
+		}
+
+		CheckBox {
+			id: objects
+			text: "Include object definition and catalog thumbnails"
+		}
+
+		CheckBox {
+			id: strict
+			text: "Stop at the first failing resource"
+		}
+
+		Row {
+			spacing: windowSpacing
 			anchors.right: parent.right
-			onClicked: { app.export() }
+
+			Button {
+				text: "Cancel"
+				enabled: app.running
+				onClicked: { app.cancel() }
+			}
+
+			Button {
+				text: "Extract"
+				enabled: !app.running
+				onClicked: { app.export() }
+			}
 		}
-		
+
+		ProgressBar {
+			width: parent.width
+			value: app.progress
+			indeterminate: app.running && app.indeterminate
+		}
+
 		Label { text: app.information }
 	}
 }
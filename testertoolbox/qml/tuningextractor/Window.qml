@@ -27,6 +27,54 @@ ApplicationWindow {
 		value: exportDirDialog.fileUrl
 	}
 
+	Binding {
+		target: app
+		property: "language"
+		value: language.currentText
+	}
+
+	Binding {
+		target: app
+		property: "secondLanguage"
+		value: secondLanguage.currentIndex > 0 ? secondLanguage.currentText : ""
+	}
+
+	Binding {
+		target: app
+		property: "format"
+		value: format.currentText
+	}
+
+	Binding {
+		target: app
+		property: "fromGame"
+		value: fromGame.checked
+	}
+
+	Binding {
+		target: app
+		property: "incremental"
+		value: incremental.checked
+	}
+
+	Binding {
+		target: app
+		property: "byPack"
+		value: byPack.checked
+	}
+
+	Binding {
+		target: app
+		property: "naming"
+		value: naming.editText
+	}
+
+	Binding {
+		target: app
+		property: "strict"
+		value: strict.checked
+	}
+
 	property real windowMargin: 8
 	property real windowSpacing: 4
 	property real fileNameWidth: 250
@@ -80,12 +128,95 @@ ApplicationWindow {
 			}
 		}
 
-		Button {
-			text: "Extract"
+		Row {
+			spacing: windowSpacing
+
+			Column {
+				spacing: windowSpacing
+
+				Label { text: "Language:" }
+
+				ComboBox {
+					id: language
+					model: app.languageList.split(",")
+				}
+			}
+
+			Column {
+				spacing: windowSpacing
+
+				Label { text: "Side by side with:" }
+
+				ComboBox {
+					id: secondLanguage
+					model: ["None"].concat(app.languageList.split(","))
+				}
+			}
+
+			Column {
+				spacing: windowSpacing
+
+				Label { text: "Format:" }
+
+				ComboBox {
+					id: format
+					model: app.formatList.split(",")
+				}
+			}
+		}
+
+		Label { text: "File Naming (layout or template):" }
+
+		ComboBox {
+			id: naming
+			editable: true
+			width: fileNameWidth
+			model: app.namingList.split(",")
+		}
+
+		CheckBox {
+			id: fromGame
+			text: "Read combined tuning from the game packages"
+		}
+
+		CheckBox {
+			id: incremental
+			text: "Skip instances unchanged since the last extraction"
+		}
+
+		CheckBox {
+			id: byPack
+			text: "Separate folders per pack"
+		}
+
+		CheckBox {
+			id: strict
+			text: "Stop at the first failing resource"
+		}
+
+		Row {
+			spacing: windowSpacing
 			anchors.right: parent.right
-			onClicked: { app.export() }
+
+			Button {
+				text: "Cancel"
+				enabled: app.running
+				onClicked: { app.cancel() }
+			}
+
+			Button {
+				text: "Extract"
+				enabled: !app.running
+				onClicked: { app.export() }
+			}
+		}
+
+		ProgressBar {
+			width: parent.width
+			value: app.progress
+			indeterminate: app.running && app.indeterminate
 		}
-		
+
 		Label { text: app.information }
 	}
 }
@@ -0,0 +1,127 @@
+import QtQuick 2.4
+import QtQuick.Controls 1.3
+import QtQuick.Dialogs 1.2
+
+ApplicationWindow {
+	FileDialog {
+		id: exportDirDialog
+		title: "Please choose a directory"
+		selectFolder: true
+	}
+
+	Binding {
+		target: app
+		property: "exportDir"
+		value: exportDirDialog.fileUrl
+	}
+
+	FileDialog {
+		id: graphFileDialog
+		title: "Please choose a file"
+		selectExisting: false
+		nameFilters: [ "Graphviz files (*.dot)", "JSON files (*.json)" ]
+		onAccepted: { app.saveGraph() }
+	}
+
+	Binding {
+		target: app
+		property: "graphFile"
+		value: graphFileDialog.fileUrl
+	}
+
+	Binding {
+		target: app
+		property: "tuning"
+		value: tuning.text
+	}
+
+	Binding {
+		target: app
+		property: "depth"
+		value: depth.value
+	}
+
+	property real windowMargin: 8
+	property real windowSpacing: 4
+	property real fileNameWidth: 250
+
+	title: "Tuning References"
+	width: 600
+	height: 500
+	minimumWidth: body.width + 2 * windowMargin + 2
+	minimumHeight: body.height + 2 * windowMargin + 2
+
+	Column {
+		id: body
+		spacing: windowSpacing
+		anchors.top: parent.top
+		anchors.left: parent.left
+		anchors.margins: windowMargin
+
+		Label { text: "Export Directory:" }
+
+		Row {
+			spacing: windowSpacing
+
+			TextField {
+				text: exportDirDialog.fileUrl
+				width: fileNameWidth
+				enabled: false
+			}
+
+			Button {
+				text: "Browse"
+				onClicked: { exportDirDialog.open() }
+			}
+		}
+
+		Label { text: "Tuning (key, instance ID or name):" }
+
+		Row {
+			spacing: windowSpacing
+
+			TextField {
+				id: tuning
+				width: fileNameWidth
+			}
+
+			Button {
+				text: "Look Up"
+				onClicked: { app.lookup() }
+			}
+		}
+
+		Row {
+			spacing: windowSpacing
+
+			Label {
+				text: "Graph depth:"
+				anchors.verticalCenter: parent.verticalCenter
+			}
+
+			SpinBox {
+				id: depth
+				minimumValue: 1
+				maximumValue: 5
+				value: 1
+			}
+
+			Button {
+				text: "Save Graph"
+				onClicked: { graphFileDialog.open() }
+			}
+		}
+
+		Label { text: app.information }
+	}
+
+	TextArea {
+		anchors.top: body.bottom
+		anchors.left: parent.left
+		anchors.right: parent.right
+		anchors.bottom: parent.bottom
+		anchors.margins: windowMargin
+		readOnly: true
+		text: app.results
+	}
+}
@@ -0,0 +1,149 @@
+import QtQuick 2.4
+import QtQuick.Controls 1.3
+import QtQuick.Dialogs 1.2
+
+ApplicationWindow {
+	FileDialog {
+		id: tuningDirDialog
+		title: "Please choose a directory"
+		selectFolder: true
+	}
+
+	Binding {
+		target: app
+		property: "tuningDir"
+		value: tuningDirDialog.fileUrl
+	}
+
+	Binding {
+		target: app
+		property: "name"
+		value: name.text
+	}
+
+	Binding {
+		target: app
+		property: "type"
+		value: type.text
+	}
+
+	Binding {
+		target: app
+		property: "instance"
+		value: instance.text
+	}
+
+	Binding {
+		target: app
+		property: "where"
+		value: where.text
+	}
+
+	property real windowMargin: 8
+	property real windowSpacing: 4
+	property real fileNameWidth: 250
+	property real fieldWidth: 160
+
+	title: "Tuning Search"
+	width: 600
+	height: 500
+	minimumWidth: body.width + 2 * windowMargin + 2
+	minimumHeight: body.height + 2 * windowMargin + 2
+
+	Column {
+		id: body
+		spacing: windowSpacing
+		anchors.top: parent.top
+		anchors.left: parent.left
+		anchors.margins: windowMargin
+
+		Label { text: "Game or Export Directory:" }
+
+		Row {
+			spacing: windowSpacing
+
+			TextField {
+				text: tuningDirDialog.fileUrl
+				width: fileNameWidth
+				enabled: false
+			}
+
+			Button {
+				text: "Browse"
+				onClicked: { tuningDirDialog.open() }
+			}
+		}
+
+		Row {
+			spacing: windowSpacing
+
+			Column {
+				spacing: windowSpacing
+
+				Label { text: "Name:" }
+
+				TextField {
+					id: name
+					width: fieldWidth
+				}
+			}
+
+			Column {
+				spacing: windowSpacing
+
+				Label { text: "Type:" }
+
+				TextField {
+					id: type
+					width: fieldWidth
+				}
+			}
+
+			Column {
+				spacing: windowSpacing
+
+				Label { text: "Instance ID:" }
+
+				TextField {
+					id: instance
+					width: fieldWidth
+				}
+			}
+		}
+
+		Label { text: "Where (path=value, separated by ;):" }
+
+		TextField {
+			id: where
+			width: 3 * fieldWidth + 2 * windowSpacing
+		}
+
+		Row {
+			spacing: windowSpacing
+
+			Button {
+				text: "Cancel"
+				enabled: app.running
+				onClicked: { app.cancel() }
+			}
+
+			Button {
+				text: "Search"
+				enabled: !app.running
+				onClicked: { app.search() }
+			}
+
+			Label { text: app.information }
+		}
+	}
+
+	TextArea {
+		anchors.top: body.bottom
+		anchors.left: parent.left
+		anchors.right: parent.right
+		anchors.bottom: parent.bottom
+		anchors.margins: windowMargin
+		readOnly: true
+		text: app.results
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningrefs"
+	"gopkg.in/qml.v1"
+)
+
+type TuningReferences struct {
+	ExportDir, Tuning, GraphFile string
+	Depth                        int
+	Results, Information         string
+
+	dir   string
+	graph *tuningrefs.Graph
+	keys  []string
+}
+
+func (d *TuningReferences) inform(text string) {
+	d.Information = text
+	qml.Changed(d, &d.Information)
+}
+
+func (d *TuningReferences) report(err error) {
+	logging.Error("Tuning reference lookup failed", "error", err)
+	d.Information = err.Error()
+	qml.Changed(d, &d.Information)
+}
+
+func (d *TuningReferences) load() (*tuningrefs.Graph, error) {
+	dir := trimPath(d.ExportDir)
+	if dir == d.dir && d.graph != nil {
+		return d.graph, nil
+	}
+	graph, err := tuningrefs.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	d.dir, d.graph = dir, graph
+	return graph, nil
+}
+
+func (d *TuningReferences) Lookup() {
+	graph, err := d.load()
+	if err != nil {
+		d.report(err)
+		return
+	}
+
+	d.keys = graph.Resolve(d.Tuning)
+	if len(d.keys) == 0 {
+		d.inform(fmt.Sprintf("No tuning matches %v.", d.Tuning))
+		return
+	}
+
+	var buffer bytes.Buffer
+	for _, key := range d.keys {
+		graph.WriteText(&buffer, key)
+	}
+	d.Results = buffer.String()
+	qml.Changed(d, &d.Results)
+
+	d.inform(fmt.Sprintf("%v matching tunings.", len(d.keys)))
+}
+
+func (d *TuningReferences) SaveGraph() {
+	if len(d.keys) != 1 {
+		d.report(errors.New("Look up a single tuning before saving its graph."))
+		return
+	}
+
+	path := trimPath(d.GraphFile)
+	file, err := os.Create(path)
+	if err != nil {
+		d.report(err)
+		return
+	}
+	defer file.Close()
+
+	neighborhood := d.graph.Neighborhood(d.keys[0], d.Depth)
+	if strings.EqualFold(filepath.Ext(path), ".dot") {
+		err = neighborhood.WriteDOT(file)
+	} else {
+		err = neighborhood.WriteJSON(file)
+	}
+	if err != nil {
+		d.report(err)
+		return
+	}
+
+	d.inform(fmt.Sprintf("Saved %v tunings and %v references.", len(neighborhood.Nodes), len(neighborhood.Edges)))
+}
+
+func createTuningReferencesWindow() error {
+	engine := qml.NewEngine()
+
+	references, err := engine.LoadFile("qrc:///qml/tuningrefs/Window.qml")
+	if err != nil {
+		return err
+	}
+
+	engineContext := engine.Context()
+	d := new(TuningReferences)
+	d.Information = "Choose an extraction directory, enter a tuning and press Look Up"
+	d.Depth = 1
+	engineContext.SetVar("app", d)
+
+	window := references.CreateWindow(nil)
+	window.Show()
+
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningextractor"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningquery"
+	"gopkg.in/qml.v1"
+)
+
+const searchLimit = 500
+
+type TuningSearch struct {
+	TuningDir, Name, Type, Instance, Where string
+	Results, Information                   string
+	Running                                bool
+
+	task    task
+	dir     string
+	tunings []*tuningextractor.Tuning
+}
+
+func (d *TuningSearch) inform(text string) {
+	d.Information = text
+	qml.Changed(d, &d.Information)
+}
+
+func (d *TuningSearch) report(err error) {
+	logging.Error("Tuning search failed", "error", err)
+	d.Information = err.Error()
+	qml.Changed(d, &d.Information)
+}
+
+func (d *TuningSearch) setRunning(running bool) {
+	d.Running = running
+	qml.Changed(d, &d.Running)
+}
+
+func (d *TuningSearch) setResults(text string) {
+	d.Results = text
+	qml.Changed(d, &d.Results)
+}
+
+func (d *TuningSearch) load(ctx context.Context, dir string) ([]*tuningextractor.Tuning, error) {
+	if dir == d.dir && d.tunings != nil {
+		return d.tunings, nil
+	}
+	d.inform("Loading tuning...")
+	tunings, err := tuningquery.Load(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	d.dir, d.tunings = dir, tunings
+	return tunings, nil
+}
+
+func (d *TuningSearch) Search() {
+	conditions, err := tuningquery.ParseConditions(d.Where)
+	if err != nil {
+		d.report(err)
+		return
+	}
+
+	dir := trimPath(d.TuningDir)
+	q := &tuningquery.Query{
+		Name:     d.Name,
+		Type:     d.Type,
+		Instance: d.Instance,
+		Where:    conditions,
+		Limit:    searchLimit,
+	}
+
+	d.task.start(func(ctx context.Context) {
+		d.setRunning(true)
+		defer d.setRunning(false)
+
+		tunings, err := d.load(ctx, dir)
+		if err == nil {
+			d.inform("Searching...")
+		}
+		var matches []*tuningquery.Match
+		if err == nil {
+			matches, err = tuningquery.Search(ctx, tunings, q)
+		}
+		if err == context.Canceled {
+			d.inform("Search cancelled.")
+			return
+		}
+		if err != nil {
+			d.report(err)
+			return
+		}
+
+		var buffer bytes.Buffer
+		tuningquery.WriteText(&buffer, matches)
+		d.setResults(buffer.String())
+
+		if len(matches) >= searchLimit {
+			d.inform(fmt.Sprintf("Showing the first %v matches.", searchLimit))
+			return
+		}
+		d.inform(fmt.Sprintf("%v matches.", len(matches)))
+	})
+}
+
+func (d *TuningSearch) Cancel() {
+	d.task.stop()
+}
+
+func createTuningSearchWindow() error {
+	engine := qml.NewEngine()
+
+	search, err := engine.LoadFile("qrc:///qml/tuningsearch/Window.qml")
+	if err != nil {
+		return err
+	}
+
+	engineContext := engine.Context()
+	d := new(TuningSearch)
+	d.Information = "Choose a game or export directory and press Search"
+	engineContext.SetVar("app", d)
+
+	window := search.CreateWindow(nil)
+	window.Show()
+
+	return nil
+}
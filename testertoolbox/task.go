@@ -0,0 +1,79 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const progressInterval = 100 * time.Millisecond
+
+type task struct {
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+}
+
+func (t *task) start(run func(ctx context.Context)) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.cancel != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+
+	go func() {
+		run(ctx)
+		t.mutex.Lock()
+		t.cancel = nil
+		t.mutex.Unlock()
+		cancel()
+	}()
+
+	return true
+}
+
+func (t *task) stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
+type throttle struct {
+	last  time.Time
+	phase string
+}
+
+func (t *throttle) ready(phase string) bool {
+	now := time.Now()
+	if phase == t.phase && now.Sub(t.last) < progressInterval {
+		return false
+	}
+	t.last = now
+	t.phase = phase
+	return true
+}
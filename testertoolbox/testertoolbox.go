@@ -24,20 +24,53 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/Fogity/TS4Tools/testertoolbox/thumbextractor"
-	"github.com/Fogity/TS4Tools/testertoolbox/tuningextractor"
+	"github.com/Fogity/TS4Tools/logging"
 	"gopkg.in/qml.v1"
 )
 
-type App struct{}
+func trimPath(path string) string {
+	return strings.TrimPrefix(path, "file:/")
+}
+
+const logLines = 500
+
+type App struct {
+	Log string
+}
+
+func (a *App) startLogging() func() {
+	buffer := logging.NewBuffer(logLines, func(text string) {
+		a.Log = text
+		qml.Changed(a, &a.Log)
+	})
+	sinks := []logging.Sink{logging.AtLevel(logging.LevelInfo, buffer)}
+
+	file, err := logging.OpenDefault("testertoolbox")
+	if err == nil {
+		sinks = append(sinks, logging.NewTextSink(file))
+	}
+	logging.SetDefault(logging.New(logging.LevelDebug, sinks...))
+
+	if err != nil {
+		logging.Warn("Log file could not be opened", "error", err)
+		return func() {}
+	}
+	logging.Info("Logging to file", "path", file.Path())
+	return func() { file.Close() }
+}
 
 func (*App) Create(tool string) {
 	switch tool {
 	case "thumbextractor":
-		thumbextractor.CreateWindow()
+		createThumbExtractorWindow()
 	case "tuningextractor":
-		tuningextractor.CreateWindow()
+		createTuningExtractorWindow()
+	case "tuningsearch":
+		createTuningSearchWindow()
+	case "tuningrefs":
+		createTuningReferencesWindow()
 	}
 }
 
@@ -58,8 +91,12 @@ func run() error {
 		return err
 	}
 
+	app := new(App)
+	stopLogging := app.startLogging()
+	defer stopLogging()
+
 	context := engine.Context()
-	context.SetVar("app", new(App))
+	context.SetVar("app", app)
 
 	window := toolbox.CreateWindow(nil)
 	window.Show()
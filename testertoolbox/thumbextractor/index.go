@@ -0,0 +1,100 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package thumbextractor
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	IndexCSV  = "csv"
+	IndexJSON = "json"
+	IndexNone = "none"
+
+	indexName = "index"
+)
+
+var IndexFormats = []string{IndexCSV, IndexJSON, IndexNone}
+
+func IsIndexFormat(format string) bool {
+	for _, f := range IndexFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+type Entry struct {
+	File     string `json:"file"`
+	Key      string `json:"key"`
+	Type     string `json:"type"`
+	Group    string `json:"group"`
+	Instance string `json:"instance"`
+	Name     string `json:"name"`
+	Pack     string `json:"pack,omitempty"`
+	Package  string `json:"package"`
+	Renamed  bool   `json:"renamed,omitempty"`
+}
+
+func writeIndexCSV(w io.Writer, entries []Entry) error {
+	c := csv.NewWriter(w)
+	c.Write([]string{"file", "key", "type", "group", "instance", "name", "pack", "package", "renamed"})
+	for _, e := range entries {
+		c.Write([]string{e.File, e.Key, e.Type, e.Group, e.Instance, e.Name, e.Pack, e.Package, fmt.Sprint(e.Renamed)})
+	}
+	c.Flush()
+	return c.Error()
+}
+
+func writeIndexJSON(w io.Writer, entries []Entry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(entries)
+}
+
+func writeIndex(folder, format string, entries []Entry) error {
+	if format == IndexNone {
+		return nil
+	}
+
+	if err := os.MkdirAll(folder, 0700); err != nil {
+		return err
+	}
+	file, err := os.Create(fmt.Sprintf("%v/%v.%v", folder, indexName, format))
+	if err != nil {
+		return err
+	}
+
+	if format == IndexCSV {
+		err = writeIndexCSV(file, entries)
+	} else {
+		err = writeIndexJSON(file, entries)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package thumbextractor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Fogity/TS4Libs/consts"
+)
+
+const (
+	ResourceTypeCasPartThumbnail  uint32 = 0x3C1AF1F2
+	ResourceTypeBodyPartThumbnail uint32 = 0x5B282D45
+	ResourceTypeObjectThumbnail   uint32 = 0x3C2A8647
+
+	resourceTypeObjectDefinition uint32 = 0xC0DB5AE7
+	resourceTypeObjectCatalog    uint32 = 0x319E4F1D
+)
+
+const AnyGroup = "any"
+
+type Choice struct {
+	Name, Description string
+	Value             uint32
+}
+
+var Types = []Choice{
+	{"caspart", "Cas part thumbnails", ResourceTypeCasPartThumbnail},
+	{"bodypart", "Body part thumbnails", ResourceTypeBodyPartThumbnail},
+	{"object", "Object and catalog thumbnails", ResourceTypeObjectThumbnail},
+}
+
+var Groups = []Choice{
+	{"female", "Female portraits", consts.ResourceGroupPortraitFemale},
+	{"male", "Male portraits", consts.ResourceGroupPortraitMale},
+}
+
+var (
+	DefaultGroups       = []uint32{consts.ResourceGroupPortraitFemale, consts.ResourceGroupPortraitMale}
+	DefaultCasPartTypes = []uint32{ResourceTypeCasPartThumbnail, ResourceTypeBodyPartThumbnail}
+	DefaultObjectTypes  = []uint32{ResourceTypeObjectThumbnail}
+)
+
+func ChoiceNames(choices []Choice) []string {
+	names := make([]string, len(choices))
+	for i, c := range choices {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func parseChoice(choices []Choice, text string) (uint32, error) {
+	for _, c := range choices {
+		if strings.EqualFold(c.Name, text) {
+			return c.Value, nil
+		}
+	}
+	value, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(text), "0x"), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Unknown value %v, expecting one of %v or a hexadecimal number.", text, strings.Join(ChoiceNames(choices), ", "))
+	}
+	return uint32(value), nil
+}
+
+func parseChoices(choices []Choice, text string) ([]uint32, error) {
+	var values []uint32
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		value, err := parseChoice(choices, part)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func ParseTypes(text string) ([]uint32, error) {
+	return parseChoices(Types, text)
+}
+
+func ParseGroups(text string) ([]uint32, bool, error) {
+	if strings.EqualFold(strings.TrimSpace(text), AnyGroup) {
+		return nil, true, nil
+	}
+	groups, err := parseChoices(Groups, text)
+	return groups, false, err
+}
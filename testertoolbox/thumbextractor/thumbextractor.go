@@ -20,8 +20,13 @@ along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
 package thumbextractor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/Fogity/TS4Libs/caspart"
@@ -29,125 +34,354 @@ import (
 	"github.com/Fogity/TS4Libs/dbpf"
 	"github.com/Fogity/TS4Libs/keys"
 	"github.com/Fogity/TS4Libs/thumbnail"
-	"gopkg.in/qml.v1"
+	"github.com/Fogity/TS4Tools/game"
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/failures"
+	"github.com/Fogity/TS4Tools/testertoolbox/naming"
 )
 
+var (
+	ErrCasPartFileMissing = errors.New("A Cas Part Package must be specified.")
+	ErrThumbFileMissing   = errors.New("A Thumbnail Package must be specified.")
+	ErrExportDirMissing   = errors.New("An Export Directory must be specified.")
+)
+
+var matchThumbnailCache = regexp.MustCompile(`(?i)^.*thumb.*\.package$`)
+
 const (
-	casPartFileMissing = "A Cas Part Package must be specified."
-	thumbFileMissing   = "A Thumbnail Package must be specified."
-	exportDirMissing   = "An Export Directory must be specified."
+	PhaseCasParts   failures.Phase = "Loading definitions"
+	PhaseThumbnails failures.Phase = "Finding thumbnails"
+	PhaseWriting    failures.Phase = "Writing thumbnails"
 )
 
-func trimPath(path string) string {
-	return strings.TrimPrefix(path, "file:/")
+type Options struct {
+	CasPartFile, ThumbFile, GameDir, ExportDir string
+	Naming, Index                              string
+	Instance                                   bool
+	Types, Groups                              []uint32
+	AnyGroup, Objects                          bool
+	Policy                                     failures.Policy
 }
 
-type Data struct {
-	CasPartFile, ThumbFile, ExportDir, Information string
+type Result struct {
+	Install          *game.Install
+	Written, Renamed int
+	Failures         *failures.Report
 }
 
-func (d *Data) inform(text string) {
-	d.Information = text
-	qml.Changed(d, &d.Information)
+type resource interface {
+	ToBytes() ([]byte, error)
 }
 
-func (d *Data) report(err error) {
-	d.Information = err.Error()
-	qml.Changed(d, &d.Information)
+type source struct {
+	pack, path string
 }
 
-func (d *Data) Export() {
-	if d.CasPartFile == "" {
-		d.inform(casPartFileMissing)
-		return
-	}
+type thumb struct {
+	key keys.Key
+	src source
+	r   resource
+}
 
-	if d.ThumbFile == "" {
-		d.inform(thumbFileMissing)
-		return
-	}
+type job struct {
+	*failures.Job
+	names   map[uint64]string
+	objects map[uint64]bool
+	thumbs  map[keys.Key]*thumb
+}
 
-	if d.ExportDir == "" {
-		d.inform(exportDirMissing)
-		return
+func (j *job) openFailed(src source, required bool, err error) error {
+	if required {
+		return err
 	}
+	return j.Report().Add("", src.path, err)
+}
 
-	casPartPack, err := dbpf.Open(trimPath(d.CasPartFile))
-	if err != nil {
-		d.report(err)
-		return
+func (j *job) name(instance uint64) string {
+	if name, ok := j.names[instance]; ok {
+		return name
 	}
+	return fmt.Sprintf("%016X", instance)
+}
 
-	thumbPack, err := dbpf.Open(trimPath(d.ThumbFile))
+func (j *job) readDefinitions(src source, required, objects bool) error {
+	logging.Debug("Reading package", "path", src.path)
+	pack, err := dbpf.Open(src.path)
 	if err != nil {
-		d.report(err)
-		return
+		return j.openFailed(src, required, err)
 	}
 
-	folder := trimPath(d.ExportDir)
-
-	casParts := make([]uint64, 0)
-	casPartNames := make(map[uint64]string)
-	for k, r := range casPartPack.ListResources(&keys.Filter{[]uint32{consts.ResourceTypeCasPart}, nil, nil}, nil, nil) {
+	for k, r := range pack.ListResources(&keys.Filter{[]uint32{consts.ResourceTypeCasPart}, nil, nil}, nil, nil) {
+		if err := j.Err(); err != nil {
+			return err
+		}
 		data, err := r.ToBytes()
 		if err != nil {
-			d.report(err)
-			return
+			if err := j.Fail(k, src.path, err); err != nil {
+				return err
+			}
+			continue
 		}
 		casPart, err := caspart.Read(data)
 		if err != nil {
-			d.report(err)
-			return
+			if err := j.Fail(k, src.path, err); err != nil {
+				return err
+			}
+			continue
 		}
-		casParts = append(casParts, k.Instance)
-		casPartNames[k.Instance] = casPart.Name
+		j.names[k.Instance] = casPart.Name
 	}
 
-	count := 0
-	for k, r := range thumbPack.ListResources(&keys.Filter{nil, []uint32{consts.ResourceGroupPortraitFemale, consts.ResourceGroupPortraitMale}, casParts}, nil, nil) {
-		data, err := r.ToBytes()
-		if err != nil {
-			d.report(err)
-			return
+	if objects {
+		for k := range pack.ListResources(&keys.Filter{[]uint32{resourceTypeObjectDefinition, resourceTypeObjectCatalog}, nil, nil}, nil, nil) {
+			j.objects[k.Instance] = true
 		}
-		thumb, err := thumbnail.Convert(data)
-		if err != nil {
-			d.report(err)
-			return
+	}
+
+	return j.Scanned()
+}
+
+func (j *job) findThumbs(src source, required bool, filters []*keys.Filter) error {
+	logging.Debug("Reading package", "path", src.path)
+	pack, err := dbpf.Open(src.path)
+	if err != nil {
+		return j.openFailed(src, required, err)
+	}
+
+	for _, filter := range filters {
+		for k, r := range pack.ListResources(filter, nil, nil) {
+			j.thumbs[k] = &thumb{k, src, r}
 		}
-		file, err := os.Create(fmt.Sprintf("%v/%v_%x.png", folder, casPartNames[k.Instance], k.Group))
-		if err != nil {
-			d.report(err)
-			return
+	}
+
+	return j.Scanned()
+}
+
+func (j *job) filters(options Options) []*keys.Filter {
+	casGroups, objectGroups := DefaultGroups, []uint32(nil)
+	if options.AnyGroup {
+		casGroups = nil
+	} else if options.Groups != nil {
+		casGroups, objectGroups = options.Groups, options.Groups
+	}
+	casTypes, objectTypes := DefaultCasPartTypes, DefaultObjectTypes
+	if len(options.Types) > 0 {
+		casTypes, objectTypes = options.Types, options.Types
+	}
+
+	filters := make([]*keys.Filter, 0, 2)
+	if len(j.names) > 0 {
+		instances := make([]uint64, 0, len(j.names))
+		for i := range j.names {
+			instances = append(instances, i)
 		}
-		_, err = file.Write(thumb)
-		if err != nil {
-			d.report(err)
-			file.Close()
-			return
+		filters = append(filters, &keys.Filter{casTypes, casGroups, instances})
+	}
+	if len(j.objects) > 0 {
+		instances := make([]uint64, 0, len(j.objects))
+		for i := range j.objects {
+			instances = append(instances, i)
 		}
+		filters = append(filters, &keys.Filter{objectTypes, objectGroups, instances})
+	}
+	return filters
+}
+
+func writeThumbnail(path string, thumb []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(thumb)
+	if err != nil {
 		file.Close()
-		count++
+		return err
+	}
+	return file.Close()
+}
+
+func exportThumbnail(t *thumb, namer *naming.Namer, name, folder string) (Entry, error) {
+	data, err := t.r.ToBytes()
+	if err != nil {
+		return Entry{}, err
+	}
+	png, err := thumbnail.Convert(data)
+	if err != nil {
+		return Entry{}, err
+	}
+	k := t.key
+	fields := naming.Fields{Type: k.Type, Group: k.Group, Instance: k.Instance, Name: name, Pack: t.src.pack, Tag: "THUM"}
+	collisions := namer.Collisions()
+	rel := namer.Path(fmt.Sprintf("%08X_%08X_%016X", k.Type, k.Group, k.Instance), fields)
+	entry := Entry{
+		File:     rel,
+		Key:      failures.FormatKey(k.Type, k.Group, k.Instance),
+		Type:     fmt.Sprintf("%08X", k.Type),
+		Group:    fmt.Sprintf("%08X", k.Group),
+		Instance: fmt.Sprintf("%016X", k.Instance),
+		Name:     name,
+		Pack:     t.src.pack,
+		Package:  t.src.path,
+		Renamed:  namer.Collisions() > collisions,
+	}
+	if entry.Renamed {
+		logging.Warn("Thumbnail renamed to avoid a collision", "file", rel, "key", entry.Key, "name", name)
 	}
+	return entry, writeThumbnail(fmt.Sprintf("%v/%v", folder, rel), png)
+}
 
-	d.inform(fmt.Sprintf("Extraction completed, %v thumbnails extracted.", count))
+func gameSources(install *game.Install) ([]source, []source) {
+	casParts := make([]source, 0)
+	thumbs := make([]source, 0)
+	for _, p := range install.Packs {
+		for _, name := range []string{game.ClientFullBuild, game.ClientDeltaBuild} {
+			if path, ok := p.Find(name); ok {
+				casParts = append(casParts, source{p.Name, path})
+				thumbs = append(thumbs, source{p.Name, path})
+			}
+		}
+		for _, path := range p.FindAll(matchThumbnailCache) {
+			thumbs = append(thumbs, source{p.Name, path})
+		}
+	}
+	return casParts, thumbs
 }
 
-func CreateWindow() error {
-	engine := qml.NewEngine()
+func Extract(ctx context.Context, options Options, progress func(failures.Event)) (result *Result, err error) {
+	if options.GameDir == "" {
+		if options.CasPartFile == "" {
+			return nil, ErrCasPartFileMissing
+		}
+
+		if options.ThumbFile == "" {
+			return nil, ErrThumbFileMissing
+		}
+	}
+
+	if options.ExportDir == "" {
+		return nil, ErrExportDirMissing
+	}
+
+	if options.Index == "" {
+		options.Index = IndexCSV
+	}
+	if !IsIndexFormat(options.Index) {
+		return nil, fmt.Errorf("Unknown index format %v, expecting one of %v.", options.Index, strings.Join(IndexFormats, ", "))
+	}
 
-	extractor, err := engine.LoadFile("qrc:///qml/thumbextractor/Window.qml")
+	layout := naming.Lookup(naming.ThumbnailLayouts, options.Naming)
+	if options.Naming == "" {
+		layout = naming.ThumbnailLayouts[0].Template
+	}
+	template, err := naming.Parse(layout)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if options.Instance && !template.Uses(naming.Instance) && !template.Uses(naming.Key) {
+		layout += "_{" + naming.Instance + "}"
+	}
+	if options.GameDir != "" && !template.Uses(naming.Pack) {
+		layout = "{" + naming.Pack + "}/" + layout
+	}
+	if layout != template.String() {
+		template, err = naming.Parse(layout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	namer := naming.NewNamer(template, ".png")
+
+	j := &job{
+		Job:     failures.NewJob(ctx, options.Policy, "thumbnails", progress),
+		names:   make(map[uint64]string),
+		objects: make(map[uint64]bool),
+		thumbs:  make(map[keys.Key]*thumb),
+	}
+	defer func() { err = j.Finish(options.ExportDir, err) }()
+	result = &Result{Failures: j.Report()}
+
+	casParts := []source{{"", options.CasPartFile}}
+	thumbs := []source{{"", options.ThumbFile}}
+	required := true
+	if options.GameDir != "" {
+		install, err := game.Open(options.GameDir)
+		if err != nil {
+			return nil, err
+		}
+		result.Install = install
+		casParts, thumbs = gameSources(install)
+		required = false
+	}
+	logging.Info("Extracting thumbnails", "game", options.GameDir, "casparts", options.CasPartFile, "thumbs", options.ThumbFile, "export", options.ExportDir, "naming", template, "types", options.Types, "groups", options.Groups, "objects", options.Objects)
+
+	if err := j.Phase(PhaseCasParts); err != nil {
+		return nil, err
+	}
+	for _, src := range casParts {
+		if err := j.readDefinitions(src, required, options.Objects); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := j.Phase(PhaseThumbnails); err != nil {
+		return nil, err
+	}
+	filters := j.filters(options)
+	for _, src := range thumbs {
+		if len(filters) == 0 {
+			break
+		}
+		if err := j.findThumbs(src, required, filters); err != nil {
+			return nil, err
+		}
 	}
 
-	context := engine.Context()
-	d := new(Data)
-	d.Information = "Enter files and press Extract"
-	context.SetVar("app", d)
+	order := make([]*thumb, 0, len(j.thumbs))
+	for _, t := range j.thumbs {
+		order = append(order, t)
+	}
+	sort.Slice(order, func(a, b int) bool {
+		x, y := order[a], order[b]
+		if x.src.pack != y.src.pack {
+			return x.src.pack < y.src.pack
+		}
+		if x.key.Instance != y.key.Instance {
+			return x.key.Instance < y.key.Instance
+		}
+		if x.key.Group != y.key.Group {
+			return x.key.Group < y.key.Group
+		}
+		return x.key.Type < y.key.Type
+	})
 
-	window := extractor.CreateWindow(nil)
-	window.Show()
+	entries := make([]Entry, 0, len(order))
+	if err := j.Writing(PhaseWriting, len(order)); err != nil {
+		return result, err
+	}
+	for _, t := range order {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if entry, err := exportThumbnail(t, namer, j.name(t.key.Instance), options.ExportDir); err != nil {
+			if err := j.Fail(t.key, t.src.path, err); err != nil {
+				return result, err
+			}
+		} else {
+			entries = append(entries, entry)
+			result.Written++
+		}
+		result.Renamed = namer.Collisions()
+		if err := j.Written(); err != nil {
+			return result, err
+		}
+	}
 
-	return nil
+	logging.Info("Thumbnails extracted", "written", result.Written, "renamed", result.Renamed, "failures", j.Report().Len())
+	if err := writeIndex(options.ExportDir, options.Index, entries); err != nil {
+		return result, err
+	}
+	return result, nil
 }
@@ -0,0 +1,169 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/failures"
+	"github.com/Fogity/TS4Tools/testertoolbox/naming"
+	"github.com/Fogity/TS4Tools/testertoolbox/thumbextractor"
+	"gopkg.in/qml.v1"
+)
+
+type ThumbExtractor struct {
+	CasPartFile, ThumbFile, ExportDir, Information string
+	GameDir                                        string
+	FromGame                                       bool
+	NamingList, Naming                             string
+	IndexList, Index                               string
+	Instance                                       bool
+	TypeList, GroupList                            string
+	AnyGroup, Objects                              bool
+	Progress                                       float64
+	Running, Indeterminate, Strict                 bool
+
+	types, groups map[string]bool
+	task          task
+	throttle      throttle
+}
+
+func (d *ThumbExtractor) SelectType(name string, checked bool) {
+	d.types[name] = checked
+}
+
+func (d *ThumbExtractor) SelectGroup(name string, checked bool) {
+	d.groups[name] = checked
+}
+
+func selected(choices []thumbextractor.Choice, checked map[string]bool) []uint32 {
+	var values []uint32
+	for _, c := range choices {
+		if checked[c.Name] {
+			values = append(values, c.Value)
+		}
+	}
+	return values
+}
+
+func (d *ThumbExtractor) inform(text string) {
+	d.Information = text
+	qml.Changed(d, &d.Information)
+}
+
+func (d *ThumbExtractor) report(err error) {
+	logging.Error("Thumbnail extraction failed", "error", err)
+	d.Information = err.Error()
+	qml.Changed(d, &d.Information)
+}
+
+func (d *ThumbExtractor) setRunning(running bool) {
+	d.Running = running
+	qml.Changed(d, &d.Running)
+}
+
+func (d *ThumbExtractor) update(e failures.Event) {
+	if !d.throttle.ready(string(e.Phase)) {
+		return
+	}
+
+	d.Indeterminate = !e.Writing
+	qml.Changed(d, &d.Indeterminate)
+
+	d.Progress = e.Fraction()
+	qml.Changed(d, &d.Progress)
+
+	d.inform(e.String())
+}
+
+func (d *ThumbExtractor) Export() {
+	options := thumbextractor.Options{
+		ExportDir: trimPath(d.ExportDir),
+		Naming:    d.Naming,
+		Index:     d.Index,
+		Instance:  d.Instance,
+		Types:     selected(thumbextractor.Types, d.types),
+		Groups:    selected(thumbextractor.Groups, d.groups),
+		AnyGroup:  d.AnyGroup,
+		Objects:   d.Objects,
+		Policy:    failures.Strict(d.Strict),
+	}
+	if d.FromGame {
+		options.GameDir = trimPath(d.GameDir)
+	} else {
+		options.CasPartFile = trimPath(d.CasPartFile)
+		options.ThumbFile = trimPath(d.ThumbFile)
+	}
+
+	d.task.start(func(ctx context.Context) {
+		d.setRunning(true)
+		defer d.setRunning(false)
+
+		result, err := thumbextractor.Extract(ctx, options, d.update)
+		if err == context.Canceled {
+			d.inform("Extraction cancelled.")
+			return
+		}
+		if err != nil {
+			d.report(err)
+			return
+		}
+
+		information := fmt.Sprintf("Extraction completed, %v thumbnails extracted, %v renamed.\nFailures: %v.", result.Written, result.Renamed, result.Failures.Summary())
+		if result.Install != nil {
+			information += "\n" + result.Install.Summary()
+		}
+		d.inform(information)
+	})
+}
+
+func (d *ThumbExtractor) Cancel() {
+	d.task.stop()
+}
+
+func createThumbExtractorWindow() error {
+	engine := qml.NewEngine()
+
+	extractor, err := engine.LoadFile("qrc:///qml/thumbextractor/Window.qml")
+	if err != nil {
+		return err
+	}
+
+	engineContext := engine.Context()
+	d := new(ThumbExtractor)
+	d.Information = "Enter files and press Extract"
+	d.NamingList = strings.Join(naming.LayoutNames(naming.ThumbnailLayouts), ",")
+	d.Naming = naming.ThumbnailLayouts[0].Name
+	d.IndexList = strings.Join(thumbextractor.IndexFormats, ",")
+	d.Index = thumbextractor.IndexCSV
+	d.TypeList = strings.Join(thumbextractor.ChoiceNames(thumbextractor.Types), ",")
+	d.GroupList = strings.Join(thumbextractor.ChoiceNames(thumbextractor.Groups), ",")
+	d.types = make(map[string]bool)
+	d.groups = make(map[string]bool)
+	engineContext.SetVar("app", d)
+
+	window := extractor.CreateWindow(nil)
+	window.Show()
+
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningdiff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+var ErrFormatUnknown = errors.New("Unknown report format.")
+
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return r.WriteMarkdown(w)
+	case FormatHTML:
+		return r.WriteHTML(w)
+	case FormatJSON:
+		return r.WriteJSON(w)
+	}
+	return ErrFormatUnknown
+}
+
+func (r *Report) Summary() string {
+	return fmt.Sprintf("%v added, %v removed, %v modified", len(r.Added), len(r.Removed), len(r.Modified))
+}
+
+func markdownCell(text string) string {
+	text = strings.Replace(text, "|", "\\|", -1)
+	return strings.Replace(text, "\n", " ", -1)
+}
+
+func writeMarkdownList(w io.Writer, title string, instances []Instance) {
+	fmt.Fprintf(w, "\n## %v (%v)\n\n", title, len(instances))
+	for _, i := range instances {
+		fmt.Fprintf(w, "- `%v` %v (%v)\n", i.Key, i.Name, i.Type)
+	}
+}
+
+func (r *Report) WriteMarkdown(out io.Writer) error {
+	w := new(bytes.Buffer)
+	fmt.Fprintf(w, "# Tuning changes\n\n")
+	fmt.Fprintf(w, "Old: `%v`  \nNew: `%v`\n\n%v\n", r.Old, r.New, r.Summary())
+
+	writeMarkdownList(w, "Added", r.Added)
+	writeMarkdownList(w, "Removed", r.Removed)
+
+	fmt.Fprintf(w, "\n## Modified (%v)\n", len(r.Modified))
+	for _, m := range r.Modified {
+		fmt.Fprintf(w, "\n### %v (%v)\n\n`%v`\n\n", m.Name, m.Type, m.Key)
+		fmt.Fprintf(w, "| Element | Old | New |\n| --- | --- | --- |\n")
+		for _, e := range m.Elements {
+			fmt.Fprintf(w, "| `%v` | %v | %v |\n", markdownCell(e.Path), markdownCell(e.Old), markdownCell(e.New))
+		}
+	}
+
+	_, err := w.WriteTo(out)
+	return err
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Tuning changes</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 2px 6px; text-align: left; }
+.old { background: #fdd; }
+.new { background: #dfd; }
+</style>
+</head>
+<body>
+<h1>Tuning changes</h1>
+<p>Old: <code>{{.Old}}</code><br>New: <code>{{.New}}</code></p>
+<p>{{.Summary}}</p>
+<h2>Added ({{len .Added}})</h2>
+<ul>
+{{range .Added}}<li><code>{{.Key}}</code> {{.Name}} ({{.Type}})</li>
+{{end}}</ul>
+<h2>Removed ({{len .Removed}})</h2>
+<ul>
+{{range .Removed}}<li><code>{{.Key}}</code> {{.Name}} ({{.Type}})</li>
+{{end}}</ul>
+<h2>Modified ({{len .Modified}})</h2>
+{{range .Modified}}<h3>{{.Name}} ({{.Type}})</h3>
+<p><code>{{.Key}}</code></p>
+<table>
+<tr><th>Element</th><th>Old</th><th>New</th></tr>
+{{range .Elements}}<tr><td><code>{{.Path}}</code></td><td class="old">{{.Old}}</td><td class="new">{{.New}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+func (r *Report) WriteHTML(w io.Writer) error {
+	return htmlReport.Execute(w, r)
+}
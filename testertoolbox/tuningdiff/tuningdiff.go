@@ -0,0 +1,315 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningdiff
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/game"
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningextractor"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningformat"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningtree"
+)
+
+type Instance struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type ElementChange struct {
+	Path string `json:"path"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+type Modification struct {
+	Instance
+	Elements []ElementChange `json:"elements"`
+}
+
+type Report struct {
+	Old      string         `json:"old"`
+	New      string         `json:"new"`
+	Added    []Instance     `json:"added"`
+	Removed  []Instance     `json:"removed"`
+	Modified []Modification `json:"modified"`
+}
+
+type entry struct {
+	key, name, kind, hash string
+	load                  func() ([]byte, error)
+}
+
+type snapshot map[string]*entry
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func loadGame(ctx context.Context, dir string) (snapshot, error) {
+	tunings, err := tuningextractor.LoadGameTunings(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(snapshot)
+	for _, t := range tunings {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := t.Render()
+		if err != nil {
+			return nil, err
+		}
+		s[t.Key] = &entry{t.Key, t.Instance.Name, t.Type, hashOf(data), t.Render}
+	}
+	return s, nil
+}
+
+func loadKeys(dir string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]struct{ Key string }
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]string)
+	for rel, e := range entries {
+		keys[rel] = e.Key
+	}
+	return keys, nil
+}
+
+func loadOutput(ctx context.Context, dir string) (snapshot, error) {
+	keys, err := loadKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(snapshot)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		key, ok := keys[filepath.ToSlash(rel)]
+		if ext := filepath.Ext(name); ext != ".xml" {
+			if ok || (strings.HasPrefix(name, "S4_") && tuningformat.IsFormat(strings.TrimPrefix(ext, "."))) {
+				return fmt.Errorf("%v contains tuning extracted as %v, only xml output can be compared.", dir, strings.TrimPrefix(ext, "."))
+			}
+			return nil
+		}
+		if !ok {
+			if !strings.HasPrefix(name, "S4_") {
+				return nil
+			}
+			key = strings.TrimSuffix(name, ".xml")
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		load := func() ([]byte, error) {
+			return ioutil.ReadFile(path)
+		}
+		s[key] = &entry{key, "", filepath.Base(filepath.Dir(path)), hashOf(data), load}
+		return nil
+	})
+	return s, err
+}
+
+func load(ctx context.Context, dir string) (snapshot, error) {
+	if _, err := game.Open(dir); err == nil {
+		return loadGame(ctx, dir)
+	}
+	return loadOutput(ctx, dir)
+}
+
+func rootName(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			for _, a := range start.Attr {
+				if a.Name.Local == "n" {
+					return a.Value
+				}
+			}
+			return ""
+		}
+	}
+}
+
+func (e *entry) instance() (Instance, error) {
+	if e.name == "" {
+		data, err := e.load()
+		if err != nil {
+			return Instance{}, err
+		}
+		e.name = rootName(data)
+	}
+	return Instance{e.key, e.name, e.kind}, nil
+}
+
+func compareElements(oldData, newData []byte) ([]ElementChange, error) {
+	oldTree, err := tuningtree.Parse(oldData)
+	if err != nil {
+		return nil, err
+	}
+	newTree, err := tuningtree.Parse(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := oldTree.Flatten()
+	newValues := newTree.Flatten()
+
+	changes := make([]ElementChange, 0)
+	for path, v := range newValues {
+		old, ok := oldValues[path]
+		if !ok || old != v {
+			changes = append(changes, ElementChange{path, old, v})
+		}
+	}
+	for path, v := range oldValues {
+		if _, ok := newValues[path]; !ok {
+			changes = append(changes, ElementChange{path, v, ""})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func sortedKeys(s snapshot) []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func Compare(ctx context.Context, oldDir, newDir string) (*Report, error) {
+	oldSnapshot, err := load(ctx, oldDir)
+	if err != nil {
+		return nil, err
+	}
+	newSnapshot, err := load(ctx, newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Info("Comparing tuning", "old", oldDir, "oldInstances", len(oldSnapshot), "new", newDir, "newInstances", len(newSnapshot))
+	r := &Report{Old: oldDir, New: newDir}
+
+	for _, key := range sortedKeys(newSnapshot) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		e := newSnapshot[key]
+		old, ok := oldSnapshot[key]
+		if !ok {
+			i, err := e.instance()
+			if err != nil {
+				return nil, err
+			}
+			r.Added = append(r.Added, i)
+			continue
+		}
+		if old.hash == e.hash {
+			continue
+		}
+		oldData, err := old.load()
+		if err != nil {
+			return nil, err
+		}
+		newData, err := e.load()
+		if err != nil {
+			return nil, err
+		}
+		elements, err := compareElements(oldData, newData)
+		if err != nil {
+			return nil, err
+		}
+		if len(elements) == 0 {
+			continue
+		}
+		e.name = rootName(newData)
+		i, err := e.instance()
+		if err != nil {
+			return nil, err
+		}
+		r.Modified = append(r.Modified, Modification{i, elements})
+	}
+
+	for _, key := range sortedKeys(oldSnapshot) {
+		if _, ok := newSnapshot[key]; ok {
+			continue
+		}
+		i, err := oldSnapshot[key].instance()
+		if err != nil {
+			return nil, err
+		}
+		r.Removed = append(r.Removed, i)
+	}
+
+	return r, nil
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(r)
+}
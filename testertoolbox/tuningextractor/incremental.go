@@ -0,0 +1,185 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningextractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Fogity/TS4Libs/tuning/combined"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningrefs"
+)
+
+const (
+	indexFile   = "index.json"
+	changesFile = "changes.json"
+)
+
+type indexEntry struct {
+	Key    string `json:"key"`
+	Hash   string `json:"hash"`
+	Source string `json:"source,omitempty"`
+}
+
+type index map[string]indexEntry
+
+func loadIndex(folder string) (index, error) {
+	file, err := os.Open(fmt.Sprintf("%v/%v", folder, indexFile))
+	if os.IsNotExist(err) {
+		return make(index), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	x := make(index)
+	if err := json.NewDecoder(file).Decode(&x); err != nil {
+		return nil, fmt.Errorf("%v is corrupt: %v", indexFile, err)
+	}
+	return x, nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(v); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (x index) save(folder string) error {
+	return writeJSON(fmt.Sprintf("%v/%v", folder, indexFile), x)
+}
+
+type Change struct {
+	Key  string `json:"key"`
+	Path string `json:"path"`
+}
+
+type Changes struct {
+	Added    []Change `json:"added"`
+	Removed  []Change `json:"removed"`
+	Modified []Change `json:"modified"`
+}
+
+func (c *Changes) String() string {
+	return fmt.Sprintf("%v added, %v removed, %v modified", len(c.Added), len(c.Removed), len(c.Modified))
+}
+
+func sortChanges(changes []Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+}
+
+func compareIndexes(previous, current index) *Changes {
+	c := new(Changes)
+	for rel, e := range current {
+		old, ok := previous[rel]
+		switch {
+		case !ok:
+			c.Added = append(c.Added, Change{e.Key, rel})
+		case old.Hash != e.Hash:
+			c.Modified = append(c.Modified, Change{e.Key, rel})
+		}
+	}
+	for rel, e := range previous {
+		if _, ok := current[rel]; !ok {
+			c.Removed = append(c.Removed, Change{e.Key, rel})
+		}
+	}
+	sortChanges(c.Added)
+	sortChanges(c.Removed)
+	sortChanges(c.Modified)
+	return c
+}
+
+func fingerprint(format string, tables ...map[int]string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%v\n", format)
+	for _, table := range tables {
+		ids := make([]int, 0, len(table))
+		for id := range table {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			fmt.Fprintf(h, "%v=%q\n", id, table[id])
+		}
+		fmt.Fprintln(h)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sourceHash(fingerprint, header string, inst combined.Instance) string {
+	data, err := xml.Marshal(inst)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%v\n%v\n", fingerprint, header)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type baseline struct {
+	index       index
+	graph       *tuningrefs.Graph
+	fingerprint string
+}
+
+func (b *baseline) unchanged(w work, source string) (indexEntry, bool) {
+	e, ok := b.index[w.rel]
+	if !ok || b.graph == nil || source == "" || e.Source != source || e.Key != w.key {
+		return e, false
+	}
+	return e, fileExists(w.path)
+}
+
+func removeStale(folder string, changes *Changes, current index) error {
+	kept := make(map[string]bool, len(current))
+	for rel := range current {
+		kept[strings.ToLower(rel)] = true
+	}
+	for _, c := range changes.Removed {
+		if kept[strings.ToLower(c.Path)] {
+			continue
+		}
+		err := os.Remove(fmt.Sprintf("%v/%v", folder, c.Path))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningextractor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/game"
+	"github.com/Fogity/TS4Tools/logging"
+)
+
+const DefaultLanguage = "ENG_US"
+
+var Languages = []string{
+	"ENG_US", "CHS_CN", "CHT_CN", "CZE_CZ", "DAN_DK", "DUT_NL", "FIN_FI",
+	"FRE_FR", "GER_DE", "ITA_IT", "JPN_JP", "KOR_KR", "NOR_NO", "POL_PL",
+	"POR_BR", "RUS_RU", "SPA_ES", "SPA_MX", "SWE_SE",
+}
+
+func IsLanguage(language string) bool {
+	for _, l := range Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseLanguages(text string) []string {
+	languages := make([]string, 0)
+	for _, l := range strings.Split(text, ",") {
+		l = strings.ToUpper(strings.TrimSpace(l))
+		if l != "" {
+			languages = append(languages, l)
+		}
+	}
+	return languages
+}
+
+func (j *job) loadLanguages(install *game.Install, languages []string, origins provenance) (map[int]string, error) {
+	if len(languages) == 0 {
+		languages = []string{DefaultLanguage}
+	}
+
+	var fallbackOrigins provenance
+	if languages[0] == DefaultLanguage {
+		fallbackOrigins = origins
+	}
+	fallback := make(map[int]string)
+	selected := false
+	for _, language := range languages {
+		selected = selected || language == DefaultLanguage
+	}
+	if selected || install.Base().HasLocale(DefaultLanguage) {
+		var err error
+		fallback, err = j.loadStrings(install, DefaultLanguage, fallbackOrigins)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		logging.Warn("Fallback language is not installed, missing strings are left empty", "language", DefaultLanguage)
+	}
+
+	tables := make([]map[int]string, len(languages))
+	for i, language := range languages {
+		if language == DefaultLanguage {
+			tables[i] = fallback
+			continue
+		}
+		var tableOrigins provenance
+		if i == 0 {
+			tableOrigins = origins
+		}
+		var err error
+		tables[i], err = j.loadStrings(install, language, tableOrigins)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(tables) == 1 {
+		strs := make(map[int]string, len(fallback))
+		for k, v := range fallback {
+			strs[k] = v
+		}
+		for k, v := range tables[0] {
+			strs[k] = v
+		}
+		return strs, nil
+	}
+
+	keys := make(map[int]bool)
+	for _, table := range append(tables, fallback) {
+		for k := range table {
+			keys[k] = true
+		}
+	}
+
+	strs := make(map[int]string, len(keys))
+	for k := range keys {
+		parts := make([]string, len(languages))
+		for i, language := range languages {
+			v, ok := tables[i][k]
+			if !ok {
+				v = fallback[k]
+			}
+			parts[i] = fmt.Sprintf("[%v] %v", language, v)
+		}
+		strs[k] = strings.Join(parts, " / ")
+	}
+
+	return strs, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningextractor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Fogity/TS4Tools/game"
+)
+
+const manifestFile = "manifest.json"
+
+type provenance map[int][]string
+
+func (p provenance) add(key int, pack string) {
+	if p == nil {
+		return
+	}
+	packs := p[key]
+	if len(packs) > 0 && packs[len(packs)-1] == pack {
+		return
+	}
+	p[key] = append(packs, pack)
+}
+
+func (p provenance) pack(key int) string {
+	packs := p[key]
+	if len(packs) == 0 {
+		return ""
+	}
+	return packs[len(packs)-1]
+}
+
+func (p provenance) count(pack string) int {
+	count := 0
+	for _, packs := range p {
+		if packs[len(packs)-1] == pack {
+			count++
+		}
+	}
+	return count
+}
+
+func (p provenance) overrides(format string) []Override {
+	ids := make([]int, 0)
+	for key, packs := range p {
+		if len(packs) > 1 {
+			ids = append(ids, key)
+		}
+	}
+	sort.Ints(ids)
+
+	overrides := make([]Override, len(ids))
+	for i, key := range ids {
+		overrides[i] = Override{fmt.Sprintf(format, uint64(key)), p[key]}
+	}
+	return overrides
+}
+
+type Override struct {
+	Key   string   `json:"key"`
+	Packs []string `json:"packs"`
+}
+
+type ManifestPack struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Tunings  int    `json:"tunings"`
+	Strings  int    `json:"strings"`
+	CasParts int    `json:"casParts"`
+}
+
+type Manifest struct {
+	Packs    []ManifestPack `json:"packs"`
+	Tunings  []Override     `json:"tunings"`
+	Strings  []Override     `json:"strings"`
+	CasParts []Override     `json:"casParts"`
+}
+
+func newManifest(install *game.Install, tunings, strs, casParts provenance) *Manifest {
+	m := new(Manifest)
+	for _, p := range install.Packs {
+		m.Packs = append(m.Packs, ManifestPack{
+			Name:     p.Name,
+			Kind:     p.Kind,
+			Tunings:  tunings.count(p.Name),
+			Strings:  strs.count(p.Name),
+			CasParts: casParts.count(p.Name),
+		})
+	}
+	m.Tunings = tunings.overrides("0x%016X")
+	m.Strings = strs.overrides("0x%08X")
+	m.CasParts = casParts.overrides("0x%016X")
+	return m
+}
+
+func (m *Manifest) write(folder string) error {
+	return writeJSON(fmt.Sprintf("%v/%v", folder, manifestFile), m)
+}
@@ -20,11 +20,20 @@ along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
 package tuningextractor
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/Fogity/TS4Libs/caspart"
 	"github.com/Fogity/TS4Libs/consts"
@@ -34,55 +43,182 @@ import (
 	"github.com/Fogity/TS4Libs/stbl"
 	"github.com/Fogity/TS4Libs/tuning"
 	"github.com/Fogity/TS4Libs/tuning/combined"
-	"gopkg.in/qml.v1"
+	"github.com/Fogity/TS4Tools/game"
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/failures"
+	"github.com/Fogity/TS4Tools/testertoolbox/naming"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningformat"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningrefs"
+)
+
+var (
+	ErrGameDirMissing   = errors.New("The Game Directory must be specified.")
+	ErrExportDirMissing = errors.New("An Export Directory must be specified.")
 )
 
 const (
-	gameDirMissing   = "The Game Directory must be specified."
-	exportDirMissing = "An Export Directory must be specified."
+	PhaseGame     failures.Phase = "Discovering game installation"
+	PhaseTunings  failures.Phase = "Loading combined tunings"
+	PhaseStrings  failures.Phase = "Loading strings"
+	PhaseCasParts failures.Phase = "Loading cas part names"
+	PhaseWriting  failures.Phase = "Writing tuning files"
 )
 
-func trimPath(path string) string {
-	return strings.TrimPrefix(path, "file:/")
+type Options struct {
+	GameDir, ExportDir            string
+	Languages                     []string
+	Format, Naming                string
+	Policy                        failures.Policy
+	Concurrency                   int
+	ByPack, FromGame, Incremental bool
 }
 
-func isPack(name string) bool {
-	return strings.HasPrefix(name, "FP") || strings.HasPrefix(name, "GP") || strings.HasPrefix(name, "EP") || strings.HasPrefix(name, "SP")
+type job struct {
+	*failures.Job
 }
 
-func loadCombinedTunings(folder string) (map[string]*combined.Combined, map[int]string, error) {
+type Result struct {
+	Install                   *game.Install
+	Written, Skipped, Renamed int
+	Changes                   *Changes
+	Failures                  *failures.Report
+}
+
+const resourceTypeCombinedTuning = 0x62E94D38
+
+type source struct {
+	pack, group string
+	g           uint32
+	ct          *combined.Combined
+}
+
+func readCombinedTunings(folder, pack string, sources []*source) ([]*source, error) {
 	infos, err := ioutil.ReadDir(folder)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	cts := make(map[string]*combined.Combined)
-	tunings := make(map[int]string)
 	for _, info := range infos {
 		if path.Ext(info.Name()) != ".62e94d38" {
 			continue
 		}
 		file, err := os.Open(fmt.Sprintf("%v/%v", folder, info.Name()))
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		data, err := ioutil.ReadAll(file)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		file.Close()
 		ct, err := combined.Read(data)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		group := info.Name()[:strings.Index(info.Name(), "!")]
-		cts[group] = ct
-		for _, e := range ct.Entries {
+		var g uint32
+		fmt.Sscan(group, &g)
+		sources = append(sources, &source{pack, group, g, ct})
+	}
+
+	return sources, nil
+}
+
+func loadCombinedTunings(folder string) ([]*source, error) {
+	sources, err := readCombinedTunings(folder, game.BaseGame, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		if !info.IsDir() || !game.IsPack(info.Name()) {
+			continue
+		}
+		sources, err = readCombinedTunings(fmt.Sprintf("%v/%v", folder, info.Name()), info.Name(), sources)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sources, nil
+}
+
+func (j *job) loadPackageTunings(install *game.Install) ([]*source, error) {
+	filter := &keys.Filter{[]uint32{resourceTypeCombinedTuning}, nil, nil}
+
+	sources := make([]*source, 0)
+	for _, p := range install.Packs {
+		for _, name := range []string{game.SimulationFullBuild, game.SimulationDeltaBuild} {
+			path, ok := p.Find(name)
+			if !ok {
+				continue
+			}
+			logging.Debug("Reading package", "path", path)
+			pack, err := dbpf.Open(path)
+			if err != nil {
+				if err := j.Report().Add("", path, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			found := make([]*source, 0)
+			instances := make(map[*source]uint64)
+			for k, r := range pack.ListResources(filter, nil, nil) {
+				if err := j.Err(); err != nil {
+					return nil, err
+				}
+				data, err := r.ToBytes()
+				if err != nil {
+					if err := j.Fail(k, path, err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				ct, err := combined.Read(data)
+				if err != nil {
+					if err := j.Fail(k, path, err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				src := &source{p.Name, fmt.Sprintf("%08x", k.Group), k.Group, ct}
+				found = append(found, src)
+				instances[src] = k.Instance
+			}
+			sort.Slice(found, func(a, b int) bool {
+				if found[a].g != found[b].g {
+					return found[a].g < found[b].g
+				}
+				return instances[found[a]] < instances[found[b]]
+			})
+			sources = append(sources, found...)
+			if err := j.Scanned(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.New("No combined tuning was found in the game directory.")
+	}
+
+	return sources, nil
+}
+
+func indexTunings(sources []*source, origins provenance) map[int]string {
+	tunings := make(map[int]string)
+	for _, src := range sources {
+		for _, e := range src.ct.Entries {
 			for _, i := range e.Instances {
 				var s int
 				fmt.Sscan(i.Id, &s)
 				if s != 0 {
 					tunings[s] = i.Name
+					origins.add(s, src.pack)
 				}
 			}
 			for _, m := range e.Modules {
@@ -90,69 +226,87 @@ func loadCombinedTunings(folder string) (map[string]*combined.Combined, map[int]
 				fmt.Sscan(m.Id, &s)
 				if s != 0 {
 					tunings[s] = m.Name
+					origins.add(s, src.pack)
 				}
 			}
 		}
 	}
 
-	return cts, tunings, nil
+	return tunings
 }
 
-func loadStrings(folder string) (map[int]string, error) {
-	infos, err := ioutil.ReadDir(folder)
-	if err != nil {
-		return nil, err
+func tuningNames(sources []*source) []string {
+	tunings := indexTunings(sources, nil)
+	names := make([]string, 0, len(tunings))
+	for _, name := range tunings {
+		names = append(names, name)
 	}
+	return names
+}
 
-	addons := make([]string, 0)
-	for _, info := range infos {
-		if !info.IsDir() {
-			continue
-		}
-		if isPack(info.Name()) {
-			addons = append(addons, info.Name())
-		}
+func LoadTuningNames(folder string) ([]string, error) {
+	sources, err := loadCombinedTunings(folder)
+	if err != nil {
+		return nil, err
 	}
+	return tuningNames(sources), nil
+}
 
-	strs := make(map[int]string)
-
-	pack, err := dbpf.Open(fmt.Sprintf("%v/Data/Client/Strings_ENG_US.package", folder))
+func LoadGameTuningNames(ctx context.Context, gameDir string) ([]string, error) {
+	tunings, err := LoadGameTunings(ctx, gameDir)
 	if err != nil {
 		return nil, err
 	}
-	for _, r := range pack.ListResources(nil, nil, nil) {
-		data, err := r.ToBytes()
-		if err != nil {
-			return nil, err
-		}
-		table, err := stbl.Read(data)
-		if err != nil {
-			return nil, err
-		}
-		for _, e := range table.Entries {
-			strs[int(e.Key)] = e.String
-		}
+	names := make([]string, len(tunings))
+	for i, t := range tunings {
+		names[i] = t.Instance.Name
 	}
+	return names, nil
+}
 
-	for _, addon := range addons {
-		pack, err := dbpf.Open(fmt.Sprintf("%v/Delta/%v/Strings_ENG_US.package", folder, addon))
+func (j *job) loadStrings(install *game.Install, language string, origins provenance) (map[int]string, error) {
+	file := fmt.Sprintf("Strings_%v.package", language)
+
+	if !install.Base().HasLocale(language) {
+		return nil, fmt.Errorf("The language %v is not installed.", language)
+	}
+
+	strs := make(map[int]string)
+
+	for _, p := range install.Packs {
+		path, ok := p.Find(file)
+		if !ok {
+			continue
+		}
+		logging.Debug("Reading package", "path", path)
+		pack, err := dbpf.Open(path)
 		if err != nil {
-			pack, err = dbpf.Open(fmt.Sprintf("%v/%v/Strings_ENG_US.package", folder, addon))
-			if err != nil {
-				continue
+			if err := j.Report().Add("", path, err); err != nil {
+				return nil, err
 			}
+			continue
 		}
-		for _, r := range pack.ListResources(nil, nil, nil) {
+		if err := j.Scanned(); err != nil {
+			return nil, err
+		}
+		for k, r := range pack.ListResources(nil, nil, nil) {
 			data, err := r.ToBytes()
 			if err != nil {
-				return nil, err
+				if err := j.Fail(k, path, err); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			table, err := stbl.Read(data)
 			if err != nil {
-				return nil, err
+				if err := j.Fail(k, path, err); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			for _, e := range table.Entries {
 				strs[int(e.Key)] = e.String
+				origins.add(int(e.Key), p.Name)
 			}
 		}
 	}
@@ -160,70 +314,59 @@ func loadStrings(folder string) (map[int]string, error) {
 	return strs, nil
 }
 
-func loadCasPartNames(folder string) (map[int]string, error) {
-	infos, err := ioutil.ReadDir(folder)
-	if err != nil {
-		return nil, err
-	}
-
-	addons := make([]string, 0)
-	for _, info := range infos {
-		if !info.IsDir() {
-			continue
-		}
-		if isPack(info.Name()) {
-			addons = append(addons, info.Name())
-		}
-	}
-
+func (j *job) loadCasPartNames(install *game.Install, origins provenance) (map[int]string, error) {
 	filter := &keys.Filter{[]uint32{consts.ResourceTypeCasPart}, nil, nil}
 
-	pack, err := dbpf.Open(fmt.Sprintf("%v/Data/Client/ClientFullBuild0.package", folder))
-	if err != nil {
-		return nil, err
-	}
-	list := pack.ListResources(filter, nil, nil)
-	pack, err = dbpf.Open(fmt.Sprintf("%v/Data/Client/ClientDeltaBuild0.package", folder))
-	if err != nil {
-		return nil, err
-	}
-	list = pack.ListResources(filter, nil, list)
-
-	for _, addon := range addons {
-		pack, err = dbpf.Open(fmt.Sprintf("%v/%v/ClientFullBuild0.package", folder, addon))
-		if err != nil {
-			fmt.Println(err)
-			continue
-		}
-		list = pack.ListResources(filter, nil, list)
-		pack, err = dbpf.Open(fmt.Sprintf("%v/Delta/%v/ClientDeltaBuild0.package", folder, addon))
-		if err != nil {
-			fmt.Println(err)
-			continue
-		}
-		list = pack.ListResources(filter, nil, list)
-	}
-
 	names := make(map[int]string)
 
-	for k, r := range list {
-		data, err := r.ToBytes()
-		if err != nil {
-			fmt.Println(err)
-			continue
-		}
-		part, err := caspart.Read(data)
-		if err != nil {
-			fmt.Println(err)
-			continue
+	for _, p := range install.Packs {
+		for _, name := range []string{game.ClientFullBuild, game.ClientDeltaBuild} {
+			path, ok := p.Find(name)
+			if !ok {
+				if p.IsBase() {
+					return nil, fmt.Errorf("%v is missing from the game directory.", name)
+				}
+				continue
+			}
+			logging.Debug("Reading package", "path", path)
+			pack, err := dbpf.Open(path)
+			if err != nil {
+				if err := j.Report().Add("", path, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for k, r := range pack.ListResources(filter, nil, nil) {
+				if err := j.Err(); err != nil {
+					return nil, err
+				}
+				data, err := r.ToBytes()
+				if err != nil {
+					if err := j.Fail(k, path, err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				part, err := caspart.Read(data)
+				if err != nil {
+					if err := j.Fail(k, path, err); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				names[int(k.Instance)] = part.Name
+				origins.add(int(k.Instance), p.Name)
+			}
+			if err := j.Scanned(); err != nil {
+				return nil, err
+			}
 		}
-		names[int(k.Instance)] = part.Name
 	}
 
 	return names, nil
 }
 
-func formatName(instance combined.Instance, group uint32) string {
+func resourceKey(instance combined.Instance) (uint32, uint64) {
 	var t uint32
 	if instance.XMLName.Local == "M" {
 		t = consts.ResourceTypeTuningModule
@@ -232,128 +375,384 @@ func formatName(instance combined.Instance, group uint32) string {
 	}
 	var i uint64
 	fmt.Sscan(instance.Id, &i)
-	return fmt.Sprintf("S4_%08X_%08X_%016X", t, group, i)
+	return t, i
 }
 
-type Data struct {
-	GameDir, ExportDir, Information string
+func formatName(instance combined.Instance, group uint32) string {
+	t, i := resourceKey(instance)
+	return fmt.Sprintf("S4_%08X_%08X_%016X", t, group, i)
 }
 
-func (d *Data) inform(text string) {
-	d.Information = text
-	qml.Changed(d, &d.Information)
+func render(tuningContext *tuning.Context, inst combined.Instance, header string) ([]byte, error) {
+	var buffer bytes.Buffer
+	tuningContext.File = &buffer
+	if err := tuningContext.Write(inst); err != nil {
+		return nil, err
+	}
+	data := buffer.Bytes()
+	if header == "" {
+		return data, nil
+	}
+
+	comment := []byte(fmt.Sprintf("<!-- %v -->%v", header, tuningContext.LineEnd))
+	position := 0
+	if bytes.HasPrefix(data, []byte("<?xml")) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			position = i + 1
+		}
+	}
+
+	output := make([]byte, 0, len(data)+len(comment))
+	output = append(output, data[:position]...)
+	output = append(output, comment...)
+	output = append(output, data[position:]...)
+	return output, nil
 }
 
-func (d *Data) report(err error) {
-	d.Information = err.Error()
-	qml.Changed(d, &d.Information)
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
 
-func (d *Data) Export() {
-	if d.GameDir == "" {
-		d.inform(gameDirMissing)
-		return
+func writeInstance(tuningContext *tuning.Context, w work, base *baseline, keys map[string]string, options Options) outcome {
+	o := outcome{w: w}
+	header := w.header
+	if options.Format != tuningformat.FormatXML {
+		header = ""
 	}
 
-	if d.ExportDir == "" {
-		d.inform(exportDirMissing)
-		return
+	o.source = sourceHash(base.fingerprint, w.header, w.inst)
+	if options.Incremental {
+		if e, ok := base.unchanged(w, o.source); ok {
+			o.hash = e.Hash
+			o.references = base.graph.References(w.key)
+			return o
+		}
 	}
 
-	gameFolder := trimPath(d.GameDir)
-	exportFolder := trimPath(d.ExportDir)
-
-	cts, tunings, err := loadCombinedTunings(exportFolder)
+	data, err := render(tuningContext, w.inst, header)
 	if err != nil {
-		d.report(err)
-		return
+		o.err = err
+		return o
 	}
 
-	strs, err := loadStrings(gameFolder)
-	if err != nil {
-		d.report(err)
-		return
+	o.references, o.err = tuningrefs.Scan(w.key, data, keys)
+	if o.err != nil {
+		return o
 	}
 
-	names, err := loadCasPartNames(gameFolder)
-	if err != nil {
-		d.report(err)
-		return
+	data, o.err = tuningformat.Encode(data, options.Format, w.header)
+	if o.err != nil {
+		return o
 	}
 
-	context := new(tuning.Context)
-	context.Indentation = "\t"
-	context.LineEnd = "\n"
-	context.AddReferences = true
-	context.Strings = strs
-	context.Tunings = tunings
-	context.CasParts = names
+	sum := sha256.Sum256(data)
+	o.hash = hex.EncodeToString(sum[:])
 
-	count := 0
+	if options.Incremental {
+		if e, ok := base.index[w.rel]; ok && e.Hash == o.hash && fileExists(w.path) {
+			return o
+		}
+	}
 
-	for group, ct := range cts {
-		dir := fmt.Sprintf("%v/%v", exportFolder, group)
-		var g uint32
-		fmt.Sscan(group, &g)
-		os.Mkdir(dir, 0700)
-		for _, entry := range ct.Entries {
-			dir := fmt.Sprintf("%v/%v", dir, entry.Type)
-			os.Mkdir(dir, 0700)
+	o.written = true
+	o.err = ioutil.WriteFile(w.path, data, 0666)
+	return o
+}
+
+type work struct {
+	path, rel, key, kind, pack, header string
+	inst                               combined.Instance
+}
+
+func describePack(install *game.Install, name string) string {
+	if p := install.Pack(name); p != nil {
+		return fmt.Sprintf("Pack: %v (%v)", p.Name, p.Kind)
+	}
+	return fmt.Sprintf("Pack: %v", name)
+}
+
+func plan(folder string, sources []*source, install *game.Install, namer *naming.Namer, options Options) []work {
+	works := make([]work, 0)
+	index := make(map[string]int)
+	dirs := make(map[string]bool)
+	add := func(src *source, kind, header string, inst combined.Instance) {
+		key := formatName(inst, src.g)
+		id := key
+		if options.ByPack {
+			id = fmt.Sprintf("%v/%v", src.pack, key)
+		}
+		t, i := resourceKey(inst)
+		fields := naming.Fields{Type: t, Group: src.g, Instance: i, Kind: kind, Name: inst.Name, Pack: src.pack, GroupDir: src.group, Tag: "XML"}
+		if namer.Uses(naming.Class) {
+			fields.Class = className(inst)
+		}
+		rel := namer.Path(id, fields)
+		path := fmt.Sprintf("%v/%v", folder, rel)
+		if i, ok := index[path]; ok {
+			works[i].inst = inst
+			works[i].pack = src.pack
+			works[i].header = header
+			return
+		}
+		if dir := filepath.Dir(path); !dirs[dir] {
+			os.MkdirAll(dir, 0700)
+			dirs[dir] = true
+		}
+		index[path] = len(works)
+		works = append(works, work{path, rel, key, kind, src.pack, header, inst})
+	}
+
+	for _, src := range sources {
+		header := describePack(install, src.pack)
+		for _, entry := range src.ct.Entries {
 			for _, inst := range entry.Instances {
-				name := formatName(inst, g)
-				path := fmt.Sprintf("%v/%v.xml", dir, name)
-				file, err := os.Create(path)
-				if err != nil {
-					d.report(err)
-					return
-				}
-				context.File = file
-				err = context.Write(inst)
-				if err != nil {
-					d.report(err)
-					return
-				}
-				file.Close()
-				count++
+				add(src, entry.Type, header, inst)
 			}
 			for _, inst := range entry.Modules {
-				name := formatName(inst, g)
-				path := fmt.Sprintf("%v/%v.xml", dir, name)
-				file, err := os.Create(path)
-				if err != nil {
-					d.report(err)
-					return
-				}
-				context.File = file
-				err = context.Write(inst)
-				if err != nil {
-					d.report(err)
-					return
-				}
-				file.Close()
-				count++
+				add(src, entry.Type, header, inst)
 			}
 		}
 	}
 
-	d.inform(fmt.Sprintf("Extraction completed, %v files extracted.", count))
+	return works
+}
+
+type outcome struct {
+	w          work
+	hash       string
+	source     string
+	written    bool
+	references []tuningrefs.Edge
+	err        error
+}
+
+func (j *job) writeAll(works []work, newContext func() *tuning.Context, base *baseline, options Options) (index, *tuningrefs.Graph, int, int, error) {
+	ctx, cancel := context.WithCancel(j.Context())
+	defer cancel()
+
+	graph := tuningrefs.NewGraph()
+	keys := make(map[string]string)
+	for _, w := range works {
+		keys[w.inst.Id] = w.key
+		graph.AddNode(tuningrefs.Node{Key: w.key, Name: w.inst.Name, Type: w.kind, Instance: w.inst.Id})
+	}
+
+	queue := make(chan work)
+	done := make(chan outcome)
+
+	var wg sync.WaitGroup
+	for i := 0; i < options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tuningContext := newContext()
+			for w := range queue {
+				done <- writeInstance(tuningContext, w, base, keys, options)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+		for _, w := range works {
+			select {
+			case queue <- w:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	current := make(index)
+	written, skipped := 0, 0
+	var failure error
+	for o := range done {
+		if failure != nil {
+			continue
+		}
+		err := o.err
+		if err == nil {
+			current[o.w.rel] = indexEntry{o.w.key, o.hash, o.source}
+			for _, e := range o.references {
+				graph.AddEdge(e)
+			}
+			if o.written {
+				written++
+			} else {
+				skipped++
+			}
+		} else if err = j.Report().Add(o.w.key, o.w.pack, o.err); err == nil {
+			if e, ok := base.index[o.w.rel]; ok {
+				current[o.w.rel] = e
+			}
+		}
+		if err == nil {
+			err = j.Written()
+		}
+		if err != nil {
+			failure = err
+			cancel()
+		}
+	}
+
+	graph.Sort()
+	return current, graph, written, skipped, failure
 }
 
-func CreateWindow() error {
-	engine := qml.NewEngine()
+func Extract(ctx context.Context, options Options, progress func(failures.Event)) (result *Result, err error) {
+	if options.GameDir == "" {
+		return nil, ErrGameDirMissing
+	}
+
+	if options.ExportDir == "" {
+		return nil, ErrExportDirMissing
+	}
+
+	for _, language := range options.Languages {
+		if !IsLanguage(language) {
+			return nil, fmt.Errorf("Unknown language %v.", language)
+		}
+	}
+
+	if options.Format == "" {
+		options.Format = tuningformat.FormatXML
+	}
+	if !tuningformat.IsFormat(options.Format) {
+		return nil, fmt.Errorf("Unknown tuning format %v.", options.Format)
+	}
+
+	layout := naming.Lookup(naming.TuningLayouts, options.Naming)
+	if options.Naming == "" {
+		layout = naming.TuningLayouts[0].Template
+	}
+	template, err := naming.Parse(layout)
+	if err != nil {
+		return nil, err
+	}
+	if options.ByPack && !template.Uses(naming.Pack) {
+		template, err = naming.Parse("{pack}/" + layout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Concurrency <= 0 {
+		options.Concurrency = runtime.NumCPU()
+	}
+
+	logging.Info("Extracting tuning", "game", options.GameDir, "export", options.ExportDir, "languages", strings.Join(options.Languages, ","), "format", options.Format, "naming", template, "fromGame", options.FromGame, "incremental", options.Incremental)
+
+	j := &job{failures.NewJob(ctx, options.Policy, "files", progress)}
+	defer func() { err = j.Finish(options.ExportDir, err) }()
+	result = &Result{Failures: j.Report()}
+
+	if err := j.Phase(PhaseGame); err != nil {
+		return nil, err
+	}
+	install, err := game.Open(options.GameDir)
+	if err != nil {
+		return nil, err
+	}
+	result.Install = install
+	logging.Debug("Game installation opened", "packs", len(install.Packs), "missing", strings.Join(install.Missing, ","))
+
+	if err := j.Phase(PhaseTunings); err != nil {
+		return nil, err
+	}
+	var sources []*source
+	if options.FromGame {
+		sources, err = j.loadPackageTunings(install)
+	} else {
+		sources, err = loadCombinedTunings(options.ExportDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tuningOrigins := make(provenance)
+	tunings := indexTunings(sources, tuningOrigins)
 
-	extractor, err := engine.LoadFile("qrc:///qml/tuningextractor/Window.qml")
+	if err := j.Phase(PhaseStrings); err != nil {
+		return nil, err
+	}
+	stringOrigins := make(provenance)
+	strs, err := j.loadLanguages(install, options.Languages, stringOrigins)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.Phase(PhaseCasParts); err != nil {
+		return nil, err
+	}
+	casPartOrigins := make(provenance)
+	names, err := j.loadCasPartNames(install, casPartOrigins)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	context := engine.Context()
-	d := new(Data)
-	d.Information = "Enter files and press Extract"
-	context.SetVar("app", d)
+	newContext := func() *tuning.Context {
+		tuningContext := new(tuning.Context)
+		tuningContext.Indentation = "\t"
+		tuningContext.LineEnd = "\n"
+		tuningContext.AddReferences = true
+		tuningContext.Strings = strs
+		tuningContext.Tunings = tunings
+		tuningContext.CasParts = names
+		return tuningContext
+	}
 
-	window := extractor.CreateWindow(nil)
-	window.Show()
+	err = newManifest(install, tuningOrigins, stringOrigins, casPartOrigins).write(options.ExportDir)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := loadIndex(options.ExportDir)
+	if err != nil {
+		return nil, err
+	}
+
+	namer := naming.NewNamer(template, "."+options.Format)
+	works := plan(options.ExportDir, sources, install, namer, options)
+	result.Renamed = namer.Collisions()
+	if err := j.Writing(PhaseWriting, len(works)); err != nil {
+		return nil, err
+	}
+
+	base := &baseline{index: previous, fingerprint: fingerprint(options.Format, strs, tunings, names)}
+	if options.Incremental {
+		if base.graph, err = tuningrefs.Load(options.ExportDir); err != nil {
+			logging.Info("Reference graph not reused, every instance is rendered", "error", err)
+		}
+	}
+
+	current, graph, written, skipped, err := j.writeAll(works, newContext, base, options)
+	result.Written, result.Skipped = written, skipped
+	if err != nil {
+		return result, err
+	}
+
+	result.Changes = compareIndexes(previous, current)
+	if err := removeStale(options.ExportDir, result.Changes, current); err != nil {
+		return result, err
+	}
+
+	if err := current.save(options.ExportDir); err != nil {
+		return result, err
+	}
+
+	if err := graph.Save(options.ExportDir); err != nil {
+		return result, err
+	}
+
+	if err := writeJSON(fmt.Sprintf("%v/%v", options.ExportDir, changesFile), result.Changes); err != nil {
+		return result, err
+	}
 
-	return nil
+	logging.Info("Tuning extracted", "written", result.Written, "skipped", result.Skipped, "renamed", result.Renamed, "changes", result.Changes, "failures", j.Report().Len())
+	return result, nil
 }
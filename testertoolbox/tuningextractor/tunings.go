@@ -0,0 +1,108 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningextractor
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/Fogity/TS4Libs/tuning"
+	"github.com/Fogity/TS4Libs/tuning/combined"
+	"github.com/Fogity/TS4Tools/game"
+	"github.com/Fogity/TS4Tools/testertoolbox/failures"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningtree"
+)
+
+type Tuning struct {
+	Key, Type, Pack string
+	Instance        combined.Instance
+}
+
+func (t *Tuning) Render() ([]byte, error) {
+	var buffer bytes.Buffer
+	tuningContext := new(tuning.Context)
+	tuningContext.Indentation = "\t"
+	tuningContext.LineEnd = "\n"
+	tuningContext.File = &buffer
+	if err := tuningContext.Write(t.Instance); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func className(inst combined.Instance) string {
+	data, err := (&Tuning{Instance: inst}).Render()
+	if err != nil {
+		return ""
+	}
+	root, err := tuningtree.Parse(data)
+	if err != nil {
+		return ""
+	}
+	return root.Attr("c")
+}
+
+func collectTunings(sources []*source) []*Tuning {
+	tunings := make([]*Tuning, 0)
+	index := make(map[string]int)
+	add := func(src *source, entry combined.Entry, inst combined.Instance) {
+		t := &Tuning{formatName(inst, src.g), entry.Type, src.pack, inst}
+		if i, ok := index[t.Key]; ok {
+			tunings[i] = t
+			return
+		}
+		index[t.Key] = len(tunings)
+		tunings = append(tunings, t)
+	}
+
+	for _, src := range sources {
+		for _, entry := range src.ct.Entries {
+			for _, inst := range entry.Instances {
+				add(src, entry, inst)
+			}
+			for _, inst := range entry.Modules {
+				add(src, entry, inst)
+			}
+		}
+	}
+
+	return tunings
+}
+
+func LoadTunings(folder string) ([]*Tuning, error) {
+	sources, err := loadCombinedTunings(folder)
+	if err != nil {
+		return nil, err
+	}
+	return collectTunings(sources), nil
+}
+
+func LoadGameTunings(ctx context.Context, gameDir string) ([]*Tuning, error) {
+	install, err := game.Open(gameDir)
+	if err != nil {
+		return nil, err
+	}
+	j := &job{failures.NewJob(ctx, failures.PolicyStrict, "files", nil)}
+	sources, err := j.loadPackageTunings(install)
+	if err != nil {
+		return nil, err
+	}
+	return collectTunings(sources), nil
+}
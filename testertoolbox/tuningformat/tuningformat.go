@@ -0,0 +1,190 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningtree"
+)
+
+const (
+	FormatXML  = "xml"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+var Formats = []string{FormatXML, FormatJSON, FormatYAML}
+
+var ErrFormatUnknown = errors.New("Unknown tuning format.")
+
+func IsFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+var kinds = map[string]string{
+	"I": "instance",
+	"M": "module",
+	"C": "class",
+	"T": "tunable",
+	"E": "enum",
+	"L": "list",
+	"U": "tuple",
+	"V": "variant",
+}
+
+type Value struct {
+	Kind       string            `json:"kind"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Value      string            `json:"value,omitempty"`
+	References []string          `json:"references,omitempty"`
+	Children   []*Value          `json:"children,omitempty"`
+}
+
+type Document struct {
+	Header string `json:"header,omitempty"`
+	Tuning *Value `json:"tuning"`
+}
+
+func Convert(n *tuningtree.Node) *Value {
+	v := &Value{Kind: n.Name, Value: n.Text, References: n.Comments}
+	if kind, ok := kinds[n.Name]; ok {
+		v.Kind = kind
+	}
+	for _, a := range n.Attrs {
+		if a.Name == "n" {
+			v.Name = a.Value
+			continue
+		}
+		if v.Attributes == nil {
+			v.Attributes = make(map[string]string)
+		}
+		v.Attributes[a.Name] = a.Value
+	}
+	for _, c := range n.Children {
+		v.Children = append(v.Children, Convert(c))
+	}
+	return v
+}
+
+func Encode(data []byte, format, header string) ([]byte, error) {
+	if format == FormatXML {
+		return data, nil
+	}
+
+	root, err := tuningtree.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{header, Convert(root)}
+
+	var buffer bytes.Buffer
+	switch format {
+	case FormatJSON:
+		err = doc.WriteJSON(&buffer)
+	case FormatYAML:
+		err = doc.WriteYAML(&buffer)
+	default:
+		err = ErrFormatUnknown
+	}
+	return buffer.Bytes(), err
+}
+
+func (d *Document) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(d)
+}
+
+func yamlString(s string) string {
+	return strconv.Quote(s)
+}
+
+type yamlWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (y *yamlWriter) line(indent int, format string, a ...interface{}) {
+	if y.err != nil {
+		return
+	}
+	_, y.err = fmt.Fprintf(y.w, "%v%v\n", strings.Repeat("  ", indent), fmt.Sprintf(format, a...))
+}
+
+func (y *yamlWriter) value(v *Value, indent int, prefix string) {
+	y.line(indent, "%vkind: %v", prefix, yamlString(v.Kind))
+	if prefix != "" {
+		indent++
+	}
+	if v.Name != "" {
+		y.line(indent, "name: %v", yamlString(v.Name))
+	}
+	if len(v.Attributes) > 0 {
+		names := make([]string, 0, len(v.Attributes))
+		for name := range v.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		y.line(indent, "attributes:")
+		for _, name := range names {
+			y.line(indent+1, "%v: %v", yamlString(name), yamlString(v.Attributes[name]))
+		}
+	}
+	if v.Value != "" {
+		y.line(indent, "value: %v", yamlString(v.Value))
+	}
+	if len(v.References) > 0 {
+		y.line(indent, "references:")
+		for _, r := range v.References {
+			y.line(indent+1, "- %v", yamlString(r))
+		}
+	}
+	if len(v.Children) > 0 {
+		y.line(indent, "children:")
+		for _, c := range v.Children {
+			y.value(c, indent+1, "- ")
+		}
+	}
+}
+
+func (d *Document) WriteYAML(w io.Writer) error {
+	y := &yamlWriter{w: w}
+	if d.Header != "" {
+		y.line(0, "header: %v", yamlString(d.Header))
+	}
+	y.line(0, "tuning:")
+	y.value(d.Tuning, 1, "")
+	return y.err
+}
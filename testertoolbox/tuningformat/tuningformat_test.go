@@ -0,0 +1,54 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningformat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestYAMLQuotesScalars(t *testing.T) {
+	for _, s := range []string{"0x1F", "0o17", ".inf", "-.inf", ".nan", "y", "n", "Y", "N", "1_000", "true", "null", "~", "12", "1e3", "plain"} {
+		d := &Document{Tuning: &Value{Kind: "tunable", Name: s, Attributes: map[string]string{s: s}, Value: s, References: []string{s}}}
+		var buffer bytes.Buffer
+		if err := d.WriteYAML(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		quoted := `"` + s + `"`
+		text := buffer.String()
+		for _, want := range []string{"name: " + quoted, quoted + ": " + quoted, "value: " + quoted, "- " + quoted} {
+			if !strings.Contains(text, want) {
+				t.Errorf("%q: missing %v in\n%v", s, want, text)
+			}
+		}
+	}
+}
+
+func TestYAMLEscapes(t *testing.T) {
+	d := &Document{Tuning: &Value{Kind: "tunable", Value: "a\"b\\c\nd"}}
+	var buffer bytes.Buffer
+	if err := d.WriteYAML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if want := `value: "a\"b\\c\nd"`; !strings.Contains(buffer.String(), want) {
+		t.Errorf("missing %v in\n%v", want, buffer.String())
+	}
+}
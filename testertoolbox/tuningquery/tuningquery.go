@@ -0,0 +1,322 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningquery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/game"
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningextractor"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningtree"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+var (
+	ErrConditionInvalid = errors.New("Condition must be of the form path or path=value.")
+	ErrInstanceInvalid  = errors.New("Instance ID is not a valid number.")
+	ErrOutputUnknown    = errors.New("Unknown output type.")
+)
+
+type Condition struct {
+	Path     []string
+	Anchored bool
+	Value    string
+	HasValue bool
+}
+
+func ParseCondition(text string) (Condition, error) {
+	var c Condition
+	text = strings.TrimSpace(text)
+	if i := strings.Index(text, "="); i >= 0 {
+		c.Value = strings.TrimSpace(text[i+1:])
+		c.HasValue = true
+		text = strings.TrimSpace(text[:i])
+	}
+	c.Anchored = strings.HasPrefix(text, "/")
+	text = strings.Trim(text, "/")
+	if text == "" {
+		return c, ErrConditionInvalid
+	}
+	c.Path = strings.Split(text, "/")
+	return c, nil
+}
+
+func ParseConditions(text string) ([]Condition, error) {
+	conditions := make([]Condition, 0)
+	for _, part := range strings.Split(text, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		c, err := ParseCondition(part)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+type Query struct {
+	Name, Type, Instance string
+	Where                []Condition
+	Limit                int
+}
+
+type Value struct {
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+type Match struct {
+	Key      string  `json:"key"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Instance string  `json:"instance"`
+	Pack     string  `json:"pack"`
+	Values   []Value `json:"values,omitempty"`
+}
+
+func Load(ctx context.Context, dir string) ([]*tuningextractor.Tuning, error) {
+	if _, err := game.Open(dir); err == nil {
+		return tuningextractor.LoadGameTunings(ctx, dir)
+	}
+	return tuningextractor.LoadTunings(dir)
+}
+
+func matchText(pattern, text string) bool {
+	if pattern == "" {
+		return true
+	}
+	pattern = strings.ToLower(pattern)
+	text = strings.ToLower(text)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.Contains(text, pattern)
+	}
+	ok, _ := path.Match(pattern, text)
+	return ok
+}
+
+func matchValue(pattern, value string) bool {
+	pattern = strings.ToLower(pattern)
+	value = strings.ToLower(value)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return pattern == value
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+var matchIndex = regexp.MustCompile(`^\[[0-9]+\]$`)
+
+func matchSegment(pattern, segment string) bool {
+	if matchIndex.MatchString(pattern) {
+		return pattern == segment
+	}
+	ok, _ := path.Match(pattern, segment)
+	return ok
+}
+
+func (c *Condition) matchPath(p string) bool {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	if len(segments) < len(c.Path) || c.Anchored && len(segments) != len(c.Path) {
+		return false
+	}
+	segments = segments[len(segments)-len(c.Path):]
+	for i, pattern := range c.Path {
+		if !matchSegment(pattern, segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+type search struct {
+	query     *Query
+	instance  uint64
+	byName    [][]uint64
+	needsTree bool
+}
+
+func newSearch(tunings []*tuningextractor.Tuning, q *Query) (*search, error) {
+	s := &search{query: q, byName: make([][]uint64, len(q.Where))}
+
+	if q.Instance != "" {
+		id, err := strconv.ParseUint(q.Instance, 0, 64)
+		if err != nil {
+			return nil, ErrInstanceInvalid
+		}
+		s.instance = id
+	}
+
+	for i, c := range q.Where {
+		s.needsTree = true
+		if !c.HasValue || c.Value == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(c.Value, 0, 64); err == nil {
+			continue
+		}
+		for _, t := range tunings {
+			if !matchValue(c.Value, t.Instance.Name) {
+				continue
+			}
+			if id, err := strconv.ParseUint(t.Instance.Id, 10, 64); err == nil {
+				s.byName[i] = append(s.byName[i], id)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+func (s *search) matchCondition(i int, value string) bool {
+	c := s.query.Where[i]
+	if !c.HasValue || matchValue(c.Value, value) {
+		return true
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	for _, ref := range s.byName[i] {
+		if ref == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *search) match(t *tuningextractor.Tuning) (*Match, error) {
+	q := s.query
+	if !matchText(q.Name, t.Instance.Name) || !matchText(q.Type, t.Type) {
+		return nil, nil
+	}
+	if q.Instance != "" {
+		id, err := strconv.ParseUint(t.Instance.Id, 10, 64)
+		if err != nil || id != s.instance {
+			return nil, nil
+		}
+	}
+
+	m := &Match{t.Key, t.Instance.Name, t.Type, t.Instance.Id, t.Pack, nil}
+	if !s.needsTree {
+		return m, nil
+	}
+
+	data, err := t.Render()
+	if err != nil {
+		return nil, err
+	}
+	tree, err := tuningtree.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := tree.Flatten()
+	for i, c := range q.Where {
+		found := false
+		for p, v := range values {
+			if c.matchPath(p) && s.matchCondition(i, v) {
+				m.Values = append(m.Values, Value{p, v})
+				found = true
+			}
+		}
+		if !found {
+			return nil, nil
+		}
+	}
+	sort.Slice(m.Values, func(i, j int) bool {
+		return m.Values[i].Path < m.Values[j].Path
+	})
+
+	return m, nil
+}
+
+func Search(ctx context.Context, tunings []*tuningextractor.Tuning, q *Query) ([]*Match, error) {
+	s, err := newSearch(tunings, q)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*Match, 0)
+	for _, t := range tunings {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		m, err := s.match(t)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			continue
+		}
+		matches = append(matches, m)
+		if q.Limit > 0 && len(matches) >= q.Limit {
+			break
+		}
+	}
+	logging.Debug("Tuning searched", "name", q.Name, "type", q.Type, "instance", q.Instance, "conditions", len(q.Where), "matches", len(matches))
+
+	return matches, nil
+}
+
+func WriteText(w io.Writer, matches []*Match) error {
+	for _, m := range matches {
+		if _, err := fmt.Fprintf(w, "%v  %v  %v  (%v)\n", m.Key, m.Type, m.Name, m.Pack); err != nil {
+			return err
+		}
+		for _, v := range m.Values {
+			if _, err := fmt.Fprintf(w, "\t%v = %v\n", v.Path, v.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func WriteJSON(w io.Writer, matches []*Match) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(matches)
+}
+
+func Write(w io.Writer, matches []*Match, output string) error {
+	switch output {
+	case OutputText:
+		return WriteText(w, matches)
+	case OutputJSON:
+		return WriteJSON(w, matches)
+	}
+	return ErrOutputUnknown
+}
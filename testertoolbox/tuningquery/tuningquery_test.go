@@ -0,0 +1,48 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningquery
+
+import "testing"
+
+func TestConditionMatchPath(t *testing.T) {
+	cases := []struct {
+		condition, path string
+		match           bool
+	}{
+		{"buff_tests/[0]/mood_type", "/buff_tests/[0]/mood_type", true},
+		{"buff_tests/[0]/mood_type", "/buff_tests/[1]/mood_type", false},
+		{"buff_tests/[0]/mood_type", "/buff_tests/0/mood_type", false},
+		{"buff_tests/[12]/mood_type", "/buff_tests/[12]/mood_type", true},
+		{"buff_tests/[12]/mood_type", "/buff_tests/[1]/mood_type", false},
+		{"buff_tests/*/mood_type", "/buff_tests/[3]/mood_type", true},
+		{"buff_tests/[a-c]*", "/buff_tests/b_value", true},
+		{"/buff_tests/[0]", "/outer/buff_tests/[0]", false},
+		{"mood_type", "/buff_tests/[0]/mood_type", true},
+	}
+	for _, c := range cases {
+		condition, err := ParseCondition(c.condition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := condition.matchPath(c.path); got != c.match {
+			t.Errorf("%v against %v: got %v, want %v", c.condition, c.path, got, c.match)
+		}
+	}
+}
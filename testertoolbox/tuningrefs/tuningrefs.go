@@ -0,0 +1,296 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningrefs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningtree"
+)
+
+const File = "references.json"
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputDOT  = "dot"
+)
+
+type Node struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Instance string `json:"instance"`
+}
+
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Path string `json:"path"`
+}
+
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	nodes    map[string]int
+	outgoing map[string][]int
+	incoming map[string][]int
+}
+
+func NewGraph() *Graph {
+	g := &Graph{Nodes: make([]Node, 0), Edges: make([]Edge, 0)}
+	g.reindex()
+	return g
+}
+
+func (g *Graph) reindex() {
+	g.nodes = make(map[string]int)
+	g.outgoing = make(map[string][]int)
+	g.incoming = make(map[string][]int)
+	for i, n := range g.Nodes {
+		g.nodes[n.Key] = i
+	}
+	for i, e := range g.Edges {
+		g.outgoing[e.From] = append(g.outgoing[e.From], i)
+		g.incoming[e.To] = append(g.incoming[e.To], i)
+	}
+}
+
+func (g *Graph) AddNode(n Node) {
+	if i, ok := g.nodes[n.Key]; ok {
+		g.Nodes[i] = n
+		return
+	}
+	g.nodes[n.Key] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, n)
+}
+
+func (g *Graph) AddEdge(e Edge) {
+	i := len(g.Edges)
+	g.Edges = append(g.Edges, e)
+	g.outgoing[e.From] = append(g.outgoing[e.From], i)
+	g.incoming[e.To] = append(g.incoming[e.To], i)
+}
+
+func (g *Graph) Node(key string) (Node, bool) {
+	i, ok := g.nodes[key]
+	if !ok {
+		return Node{Key: key}, false
+	}
+	return g.Nodes[i], true
+}
+
+func Scan(from string, data []byte, keys map[string]string) ([]Edge, error) {
+	root, err := tuningtree.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]Edge, 0)
+	root.Walk(func(path string, n *tuningtree.Node) {
+		if n.Text == "" {
+			return
+		}
+		to, ok := keys[n.Text]
+		if !ok || to == from {
+			return
+		}
+		edges = append(edges, Edge{from, to, path})
+	})
+	return edges, nil
+}
+
+func (g *Graph) Sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Key < g.Nodes[j].Key
+	})
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Path < b.Path
+	})
+	edges := g.Edges[:0]
+	for _, e := range g.Edges {
+		if len(edges) == 0 || e != edges[len(edges)-1] {
+			edges = append(edges, e)
+		}
+	}
+	g.Edges = edges
+	g.reindex()
+}
+
+func Load(folder string) (*Graph, error) {
+	file, err := os.Open(fmt.Sprintf("%v/%v", folder, File))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	g := new(Graph)
+	if err := json.NewDecoder(file).Decode(g); err != nil {
+		return nil, fmt.Errorf("%v is corrupt: %v", File, err)
+	}
+	g.reindex()
+	return g, nil
+}
+
+func (g *Graph) Save(folder string) error {
+	file, err := os.Create(fmt.Sprintf("%v/%v", folder, File))
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(file).Encode(g); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (g *Graph) Resolve(text string) []string {
+	text = strings.TrimSpace(text)
+	if _, ok := g.nodes[text]; ok {
+		return []string{text}
+	}
+
+	keys := make([]string, 0)
+	if id, err := strconv.ParseUint(text, 0, 64); err == nil {
+		instance := strconv.FormatUint(id, 10)
+		for _, n := range g.Nodes {
+			if n.Instance == instance {
+				keys = append(keys, n.Key)
+			}
+		}
+		return keys
+	}
+
+	for _, n := range g.Nodes {
+		if strings.EqualFold(n.Name, text) {
+			keys = append(keys, n.Key)
+		}
+	}
+	return keys
+}
+
+func (g *Graph) edges(indices []int) []Edge {
+	edges := make([]Edge, len(indices))
+	for i, index := range indices {
+		edges[i] = g.Edges[index]
+	}
+	return edges
+}
+
+func (g *Graph) References(key string) []Edge {
+	return g.edges(g.outgoing[key])
+}
+
+func (g *Graph) ReferencedBy(key string) []Edge {
+	return g.edges(g.incoming[key])
+}
+
+func (g *Graph) Neighborhood(key string, depth int) *Graph {
+	n := NewGraph()
+	seen := map[string]bool{key: true}
+	edges := make(map[int]bool)
+	frontier := []string{key}
+
+	for d := 0; d < depth && len(frontier) > 0; d++ {
+		next := make([]string, 0)
+		visit := func(indices []int, other func(Edge) string) {
+			for _, i := range indices {
+				edges[i] = true
+				k := other(g.Edges[i])
+				if !seen[k] {
+					seen[k] = true
+					next = append(next, k)
+				}
+			}
+		}
+		for _, k := range frontier {
+			visit(g.outgoing[k], func(e Edge) string { return e.To })
+			visit(g.incoming[k], func(e Edge) string { return e.From })
+		}
+		frontier = next
+	}
+
+	for k := range seen {
+		node, _ := g.Node(k)
+		n.AddNode(node)
+	}
+	for i := range edges {
+		n.AddEdge(g.Edges[i])
+	}
+	n.Sort()
+	return n
+}
+
+func (g *Graph) label(key string) string {
+	if n, ok := g.Node(key); ok && n.Name != "" {
+		return fmt.Sprintf("%v (%v)", n.Name, n.Type)
+	}
+	return key
+}
+
+func (g *Graph) WriteText(w io.Writer, key string) error {
+	n, _ := g.Node(key)
+	fmt.Fprintf(w, "%v  %v  %v\n", key, n.Type, n.Name)
+
+	fmt.Fprintf(w, "\nReferenced by:\n")
+	for _, e := range g.ReferencedBy(key) {
+		fmt.Fprintf(w, "\t%v  %v  %v\n", e.From, g.label(e.From), e.Path)
+	}
+
+	fmt.Fprintf(w, "\nReferences:\n")
+	for _, e := range g.References(key) {
+		fmt.Fprintf(w, "\t%v  %v  %v\n", e.To, g.label(e.To), e.Path)
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(g)
+}
+
+func (g *Graph) WriteDOT(w io.Writer) error {
+	fmt.Fprintf(w, "digraph references {\n\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(w, "\t%q [label=%q];\n", n.Key, g.label(n.Key))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "\t%q -> %q [label=%q];\n", e.From, e.To, e.Path)
+	}
+	_, err := fmt.Fprintf(w, "}\n")
+	return err
+}
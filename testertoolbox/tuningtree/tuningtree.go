@@ -0,0 +1,142 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tuningtree
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Attr struct {
+	Name, Value string
+}
+
+type Node struct {
+	Name     string
+	Attrs    []Attr
+	Text     string
+	Comments []string
+	Children []*Node
+}
+
+func Parse(data []byte) (*Node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *Node
+	stack := make([]*Node, 0)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &Node{Name: t.Name.Local}
+			for _, a := range t.Attr {
+				n.Attrs = append(n.Attrs, Attr{a.Name.Local, a.Value})
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			n := stack[len(stack)-1]
+			n.Text = strings.TrimSpace(n.Text)
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		case xml.Comment:
+			if len(stack) > 0 {
+				n := stack[len(stack)-1]
+				n.Comments = append(n.Comments, strings.TrimSpace(string(t)))
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
+
+func (n *Node) Attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *Node) label(position int) string {
+	if name := n.Attr("n"); name != "" {
+		return name
+	}
+	return fmt.Sprintf("[%v]", position)
+}
+
+func (n *Node) value() string {
+	parts := make([]string, 0)
+	for _, a := range n.Attrs {
+		if a.Name == "n" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%v=%v", a.Name, a.Value))
+	}
+	if n.Text != "" {
+		parts = append(parts, n.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (n *Node) Walk(visit func(path string, n *Node)) {
+	n.walk("", visit)
+}
+
+func (n *Node) walk(path string, visit func(string, *Node)) {
+	visit(path, n)
+	for i, c := range n.Children {
+		c.walk(path+"/"+c.label(i), visit)
+	}
+}
+
+func (n *Node) Flatten() map[string]string {
+	values := make(map[string]string)
+	n.Walk(func(path string, n *Node) {
+		if path == "" {
+			path = "/"
+		}
+		if v := n.value(); v != "" || len(n.Children) == 0 {
+			values[path] = v
+		}
+	})
+	return values
+}
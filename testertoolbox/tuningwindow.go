@@ -0,0 +1,142 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/logging"
+	"github.com/Fogity/TS4Tools/testertoolbox/failures"
+	"github.com/Fogity/TS4Tools/testertoolbox/naming"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningextractor"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningformat"
+	"gopkg.in/qml.v1"
+)
+
+type TuningExtractor struct {
+	GameDir, ExportDir, Information string
+	LanguageList, FormatList        string
+	Language, SecondLanguage        string
+	Format, NamingList, Naming      string
+	ByPack, FromGame, Incremental   bool
+	Strict                          bool
+	Progress                        float64
+	Running, Indeterminate          bool
+
+	task     task
+	throttle throttle
+}
+
+func (d *TuningExtractor) inform(text string) {
+	d.Information = text
+	qml.Changed(d, &d.Information)
+}
+
+func (d *TuningExtractor) report(err error) {
+	logging.Error("Tuning extraction failed", "error", err)
+	d.Information = err.Error()
+	qml.Changed(d, &d.Information)
+}
+
+func (d *TuningExtractor) setRunning(running bool) {
+	d.Running = running
+	qml.Changed(d, &d.Running)
+}
+
+func (d *TuningExtractor) update(e failures.Event) {
+	if !d.throttle.ready(string(e.Phase)) {
+		return
+	}
+
+	d.Indeterminate = !e.Writing
+	qml.Changed(d, &d.Indeterminate)
+
+	d.Progress = e.Fraction()
+	qml.Changed(d, &d.Progress)
+
+	d.inform(e.String())
+}
+
+func (d *TuningExtractor) Export() {
+	languages := []string{d.Language}
+	if d.SecondLanguage != "" && d.SecondLanguage != d.Language {
+		languages = append(languages, d.SecondLanguage)
+	}
+
+	options := tuningextractor.Options{
+		GameDir:     trimPath(d.GameDir),
+		ExportDir:   trimPath(d.ExportDir),
+		Languages:   languages,
+		Format:      d.Format,
+		Naming:      d.Naming,
+		Policy:      failures.Strict(d.Strict),
+		ByPack:      d.ByPack,
+		FromGame:    d.FromGame,
+		Incremental: d.Incremental,
+	}
+
+	d.task.start(func(ctx context.Context) {
+		d.setRunning(true)
+		defer d.setRunning(false)
+
+		result, err := tuningextractor.Extract(ctx, options, d.update)
+		if err == context.Canceled {
+			d.inform("Extraction cancelled.")
+			return
+		}
+		if err != nil {
+			d.report(err)
+			return
+		}
+
+		d.inform(fmt.Sprintf("Extraction completed, %v files extracted, %v unchanged, %v renamed.\nTuning changes: %v.\nFailures: %v.\n%v", result.Written, result.Skipped, result.Renamed, result.Changes, result.Failures.Summary(), result.Install.Summary()))
+	})
+}
+
+func (d *TuningExtractor) Cancel() {
+	d.task.stop()
+}
+
+func createTuningExtractorWindow() error {
+	engine := qml.NewEngine()
+
+	extractor, err := engine.LoadFile("qrc:///qml/tuningextractor/Window.qml")
+	if err != nil {
+		return err
+	}
+
+	engineContext := engine.Context()
+	d := new(TuningExtractor)
+	d.Information = "Enter files and press Extract"
+	d.LanguageList = strings.Join(tuningextractor.Languages, ",")
+	d.Language = tuningextractor.DefaultLanguage
+	d.FormatList = strings.Join(tuningformat.Formats, ",")
+	d.Format = tuningformat.FormatXML
+	d.NamingList = strings.Join(naming.LayoutNames(naming.TuningLayouts), ",")
+	d.Naming = naming.TuningLayouts[0].Name
+	engineContext.SetVar("app", d)
+
+	window := extractor.CreateWindow(nil)
+	window.Show()
+
+	return nil
+}
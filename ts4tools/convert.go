@@ -0,0 +1,105 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Fogity/TS4Tools/moddertoolbox/converter"
+)
+
+func init() {
+	register(&command{
+		name:    "convert",
+		args:    "NUMBER...",
+		summary: "Show signed, unsigned, hexadecimal, binary, octal and byte views of numbers.\nNumbers may be given as decimal, 0x hexadecimal, 0b binary, 0o octal or bytes\n(e.g. \"FB FF FF FF\", \"be:FFFFFFFB\").",
+		setup:   convertNumbers,
+	})
+	register(&command{
+		name:    "convert key",
+		args:    "KEY...",
+		summary: "Show a resource key in every supported notation.\nKeys may be given as S4_TTTTTTTT_GGGGGGGG_IIIIIIIIIIIIIIII, T:G:I in hexadecimal\nor s4pe style 0xTTTTTTTT-0xGGGGGGGG-0xIIIIIIIIIIIIIIII.",
+		setup:   convertKeys,
+	})
+}
+
+func convertNumbers(flags *flag.FlagSet) func([]string) error {
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usagef("expecting at least 1 number")
+		}
+
+		for i, text := range args {
+			result, err := converter.Convert(text)
+			if err != nil {
+				return fmt.Errorf("%v: %v", text, err)
+			}
+			if result == nil {
+				continue
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%v\n", text)
+			fmt.Printf("  Unsigned 32:   %v\n", result.Unsigned32)
+			fmt.Printf("  Signed 32:     %v\n", result.Signed32)
+			fmt.Printf("  Unsigned 64:   %v\n", result.Unsigned64)
+			fmt.Printf("  Signed 64:     %v\n", result.Signed64)
+			fmt.Printf("  Hexadecimal:   %v\n", result.Hex)
+			fmt.Printf("  Binary:        %v\n", result.Binary)
+			fmt.Printf("  Octal:         %v\n", result.Octal)
+			fmt.Printf("  Little Endian: %v\n", result.LittleEndian)
+			fmt.Printf("  Big Endian:    %v\n", result.BigEndian)
+		}
+
+		return nil
+	}
+}
+
+func convertKeys(flags *flag.FlagSet) func([]string) error {
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usagef("expecting at least 1 key")
+		}
+
+		for i, text := range args {
+			result, err := converter.ConvertKey(text)
+			if err != nil {
+				return fmt.Errorf("%v: %v", text, err)
+			}
+			if result == nil {
+				continue
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%v\n", text)
+			fmt.Printf("  Type:      %v (%v)\n", result.Type, result.TypeName)
+			fmt.Printf("  Group:     %v\n", result.Group)
+			fmt.Printf("  Instance:  %v\n", result.Instance)
+			fmt.Printf("  S4 Name:   %v\n", result.S4)
+			fmt.Printf("  Triplet:   %v\n", result.Triplet)
+			fmt.Printf("  s4pe:      %v\n", result.S4pe)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningdiff"
+)
+
+func init() {
+	register(&command{
+		name:    "tuning diff",
+		args:    "--old DIR --new DIR [--output md|html|json] [--out FILE]",
+		summary: "Report tuning instances added, removed and modified between two game\ninstallations or two tuning extraction outputs.",
+		setup:   tuningDiff,
+	})
+}
+
+func tuningDiff(flags *flag.FlagSet) func([]string) error {
+	oldDir := flags.String("old", "", "old game installation or extraction `directory`")
+	newDir := flags.String("new", "", "new game installation or extraction `directory`")
+	output := flags.String("output", tuningdiff.FormatMarkdown, "output `type`, md, html or json")
+	out := flags.String("out", "", "report `file`, defaults to standard output")
+
+	return func(args []string) error {
+		if err := required("old", *oldDir); err != nil {
+			return err
+		}
+		if err := required("new", *newDir); err != nil {
+			return err
+		}
+		switch *output {
+		case tuningdiff.FormatMarkdown, tuningdiff.FormatHTML, tuningdiff.FormatJSON:
+		default:
+			return usagef("unknown output %q", *output)
+		}
+
+		ctx, cancel := interruptible()
+		defer cancel()
+
+		report, err := tuningdiff.Compare(ctx, *oldDir, *newDir)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			file, err := os.Create(*out)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if err := report.Write(w, *output); err != nil {
+			return err
+		}
+
+		if *out != "" {
+			fmt.Printf("Tuning changes: %v.\n", report.Summary())
+		}
+		return nil
+	}
+}
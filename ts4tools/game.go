@@ -0,0 +1,54 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Fogity/TS4Tools/game"
+)
+
+func init() {
+	register(&command{
+		name:    "game info",
+		args:    "--game DIR",
+		summary: "Show the packs, locales and missing files of a game installation.",
+		setup:   gameInfo,
+	})
+}
+
+func gameInfo(flags *flag.FlagSet) func([]string) error {
+	gameDir := flags.String("game", "", "game installation `directory`")
+
+	return func(args []string) error {
+		if err := required("game", *gameDir); err != nil {
+			return err
+		}
+
+		install, err := game.Open(*gameDir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(install.Report())
+		return nil
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Fogity/TS4Tools/moddertoolbox/hasher"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningextractor"
+)
+
+func init() {
+	register(&command{
+		name:    "hash",
+		args:    "[--format hex|dec] STRING...",
+		summary: "Print the FNV hashes of each string.",
+		setup:   hashStrings,
+	})
+	register(&command{
+		name:    "hash batch",
+		args:    "[--in FILE] [--output table|csv|json] [--format hex|dec]",
+		summary: "Print the FNV hashes of every name read from a file or stdin, one per line.",
+		setup:   hashBatch,
+	})
+	register(&command{
+		name:    "hash dict add",
+		args:    "--dict FILE [--game DIR] [--tunings DIR] [--words FILE]... [NAME...]",
+		summary: "Add names to a reverse lookup dictionary, creating it if needed.",
+		setup:   hashDictAdd,
+	})
+	register(&command{
+		name:    "hash lookup",
+		args:    "--dict FILE HASH...",
+		summary: "Find the names in a dictionary that produce each hash.",
+		setup:   hashLookup,
+	})
+}
+
+func checkNumberFormat(format string) error {
+	if format != hasher.NumberFormatHex && format != hasher.NumberFormatDec {
+		return usagef("unknown format %q", format)
+	}
+	return nil
+}
+
+func hashStrings(flags *flag.FlagSet) func([]string) error {
+	format := flags.String("format", hasher.NumberFormatHex, "number `format`, hex or dec")
+
+	return func(args []string) error {
+		if err := checkNumberFormat(*format); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return usagef("expecting at least 1 string")
+		}
+
+		for i, text := range args {
+			if i > 0 {
+				fmt.Println()
+			}
+			h := hasher.Calculate(text)
+			fmt.Printf("%v\n", text)
+			fmt.Printf("  FNV 24:          %v\n", hasher.Format32(h.Fnv24, *format))
+			fmt.Printf("  FNV 32:          %v\n", hasher.Format32(h.Fnv32, *format))
+			fmt.Printf("  FNV 32 High Bit: %v\n", hasher.Format32(h.Fnv32High, *format))
+			fmt.Printf("  FNV 64:          %v\n", hasher.Format64(h.Fnv64, *format))
+			fmt.Printf("  FNV 64 High Bit: %v\n", hasher.Format64(h.Fnv64High, *format))
+		}
+
+		return nil
+	}
+}
+
+func hashBatch(flags *flag.FlagSet) func([]string) error {
+	in := flags.String("in", "", "`file` to read names from, stdin if omitted")
+	output := flags.String("output", hasher.OutputTable, "output `type`, table, csv or json")
+	format := flags.String("format", hasher.NumberFormatHex, "number `format`, hex or dec")
+
+	return func(args []string) error {
+		if err := checkNumberFormat(*format); err != nil {
+			return err
+		}
+		switch *output {
+		case hasher.OutputTable, hasher.OutputCSV, hasher.OutputJSON:
+		default:
+			return usagef("unknown output %q", *output)
+		}
+		if len(args) != 0 {
+			return usagef("unexpected arguments")
+		}
+
+		var r io.Reader = os.Stdin
+		if *in != "" {
+			file, err := os.Open(*in)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			r = file
+		}
+
+		names, err := hasher.ReadNames(r)
+		if err != nil {
+			return err
+		}
+
+		return hasher.WriteBatch(os.Stdout, hasher.Batch(names), *output, *format)
+	}
+}
+
+func openDictionary(path string) (*hasher.Dictionary, error) {
+	d, err := hasher.LoadDictionary(path)
+	if os.IsNotExist(err) {
+		return hasher.NewDictionary(), nil
+	}
+	return d, err
+}
+
+func hashDictAdd(flags *flag.FlagSet) func([]string) error {
+	dict := flags.String("dict", "", "dictionary `file`")
+	tunings := flags.String("tunings", "", "`directory` of combined tuning files to collect tuning names from")
+	gameDir := flags.String("game", "", "game installation `directory` to collect tuning names from")
+	var words stringList
+	flags.Var(&words, "words", "`file` with names to add, one per line, may be repeated")
+
+	return func(args []string) error {
+		if err := required("dict", *dict); err != nil {
+			return err
+		}
+
+		d, err := openDictionary(*dict)
+		if err != nil {
+			return err
+		}
+		before := d.Len()
+
+		if *tunings != "" {
+			names, err := tuningextractor.LoadTuningNames(*tunings)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				d.Add(name)
+			}
+		}
+
+		if *gameDir != "" {
+			ctx, cancel := interruptible()
+			names, err := tuningextractor.LoadGameTuningNames(ctx, *gameDir)
+			cancel()
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				d.Add(name)
+			}
+		}
+
+		for _, path := range words {
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			names, err := hasher.ReadNames(file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				d.Add(name)
+			}
+		}
+
+		for _, name := range args {
+			d.Add(name)
+		}
+
+		if err := d.Save(*dict); err != nil {
+			return err
+		}
+
+		fmt.Printf("Added %v names, dictionary contains %v names.\n", d.Len()-before, d.Len())
+		return nil
+	}
+}
+
+func hashLookup(flags *flag.FlagSet) func([]string) error {
+	dict := flags.String("dict", "", "dictionary `file`")
+
+	return func(args []string) error {
+		if err := required("dict", *dict); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return usagef("expecting at least 1 hash")
+		}
+
+		d, err := hasher.LoadDictionary(*dict)
+		if err != nil {
+			return err
+		}
+
+		for _, text := range args {
+			value, err := hasher.ParseHash(text)
+			if err != nil {
+				return fmt.Errorf("%v: %v", text, err)
+			}
+			matches := d.Lookup(value)
+			if len(matches) == 0 {
+				fmt.Printf("%v: no match\n", text)
+				continue
+			}
+			for _, m := range matches {
+				fmt.Printf("%v: %v (%v)\n", text, m.Name, m.Kind)
+			}
+		}
+
+		return nil
+	}
+}
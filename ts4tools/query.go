@@ -0,0 +1,85 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningquery"
+)
+
+func init() {
+	register(&command{
+		name:    "tuning query",
+		args:    "--tunings DIR [--name NAME] [--type TYPE] [--instance ID] [--where PATH=VALUE]...",
+		summary: "Find tuning instances in a game installation or in the combined tuning files\nof an export directory. Names, types and values match case insensitively and\nmay contain * and ? wildcards. A where path matches the end of an element\npath, e.g. mood_type or buff_tests/*/mood_type, unless it starts with /. A\nvalue that is not a number also matches references to tunings with that name.\nUnnamed list entries are selected by index, e.g. buff_tests/[0]/mood_type.",
+		setup:   tuningQuery,
+	})
+}
+
+func tuningQuery(flags *flag.FlagSet) func([]string) error {
+	dir := flags.String("tunings", "", "game installation or export `directory`")
+	name := flags.String("name", "", "instance name `pattern`")
+	kind := flags.String("type", "", "tuning type `pattern`, e.g. buff")
+	instance := flags.String("instance", "", "instance `ID`, decimal or 0x prefixed hex")
+	var where stringList
+	flags.Var(&where, "where", "`condition` on tuning values, path or path=value, may be repeated")
+	output := flags.String("output", tuningquery.OutputText, "output `type`, text or json")
+	limit := flags.Int("limit", 0, "maximum `number` of results, 0 for no limit")
+
+	return func(args []string) error {
+		if err := required("tunings", *dir); err != nil {
+			return err
+		}
+		switch *output {
+		case tuningquery.OutputText, tuningquery.OutputJSON:
+		default:
+			return usagef("unknown output %q", *output)
+		}
+		if len(args) != 0 {
+			return usagef("unexpected arguments")
+		}
+
+		q := &tuningquery.Query{Name: *name, Type: *kind, Instance: *instance, Limit: *limit}
+		for _, text := range where {
+			c, err := tuningquery.ParseCondition(text)
+			if err != nil {
+				return usagef("%v", err)
+			}
+			q.Where = append(q.Where, c)
+		}
+
+		ctx, cancel := interruptible()
+		defer cancel()
+
+		tunings, err := tuningquery.Load(ctx, *dir)
+		if err != nil {
+			return err
+		}
+
+		matches, err := tuningquery.Search(ctx, tunings, q)
+		if err != nil {
+			return err
+		}
+
+		return tuningquery.Write(os.Stdout, matches, *output)
+	}
+}
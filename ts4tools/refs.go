@@ -0,0 +1,85 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningrefs"
+)
+
+func init() {
+	register(&command{
+		name:    "tuning refs",
+		args:    "--export DIR [--output text|json|dot] [--depth N] TUNING",
+		summary: "Show which tunings reference a tuning and which tunings it references, using\nthe reference index written by tuning extract. TUNING is a file key such as\nS4_xxxxxxxx_xxxxxxxx_xxxxxxxxxxxxxxxx, an instance ID or an instance name.\nThe json and dot outputs contain the neighborhood of the tuning up to the\ngiven depth, dot can be rendered with Graphviz.",
+		setup:   tuningRefs,
+	})
+}
+
+func tuningRefs(flags *flag.FlagSet) func([]string) error {
+	exportDir := flags.String("export", "", "extraction `directory` containing "+tuningrefs.File)
+	output := flags.String("output", tuningrefs.OutputText, "output `type`, text, json or dot")
+	depth := flags.Int("depth", 1, "neighborhood `depth` for json and dot output")
+
+	return func(args []string) error {
+		if err := required("export", *exportDir); err != nil {
+			return err
+		}
+		switch *output {
+		case tuningrefs.OutputText, tuningrefs.OutputJSON, tuningrefs.OutputDOT:
+		default:
+			return usagef("unknown output %q", *output)
+		}
+		if len(args) != 1 {
+			return usagef("expecting 1 tuning")
+		}
+
+		graph, err := tuningrefs.Load(*exportDir)
+		if err != nil {
+			return err
+		}
+
+		keys := graph.Resolve(args[0])
+		if len(keys) == 0 {
+			return fmt.Errorf("No tuning matches %v.", args[0])
+		}
+		if len(keys) > 1 && *output != tuningrefs.OutputText {
+			return errors.New("Several tunings match, specify one of: " + strings.Join(keys, ", "))
+		}
+
+		switch *output {
+		case tuningrefs.OutputJSON:
+			return graph.Neighborhood(keys[0], *depth).WriteJSON(os.Stdout)
+		case tuningrefs.OutputDOT:
+			return graph.Neighborhood(keys[0], *depth).WriteDOT(os.Stdout)
+		}
+		for _, key := range keys {
+			if err := graph.WriteText(os.Stdout, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
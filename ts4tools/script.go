@@ -0,0 +1,53 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Fogity/TS4Libs/script"
+)
+
+func init() {
+	register(&command{
+		name:    "script run",
+		args:    "FILE",
+		summary: "Run an engine script.",
+		setup:   scriptRun,
+	})
+}
+
+func scriptRun(flags *flag.FlagSet) func([]string) error {
+	return func(args []string) error {
+		if len(args) != 1 {
+			return usagef("expecting 1 argument, found %v", len(args))
+		}
+
+		fmt.Printf("Running script...\n")
+
+		if err := script.RunFile(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Script run sucessfully.\n")
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/testertoolbox/failures"
+	"github.com/Fogity/TS4Tools/testertoolbox/naming"
+	"github.com/Fogity/TS4Tools/testertoolbox/thumbextractor"
+)
+
+func init() {
+	register(&command{
+		name:    "thumb extract",
+		args:    "(--caspart PKG --thumbs PKG | --game DIR) --out DIR [--naming TEMPLATE]\n    [--instance] [--index FORMAT] [--types LIST] [--groups LIST] [--objects] [--strict]",
+		summary: "Extract cas part and object thumbnails as PNG images, either from a pair of\npackages or from the base game and every pack of a game installation, one\nfolder per pack. An index maps every written image to its resource key, name and pack.",
+		setup:   thumbExtract,
+	})
+}
+
+func thumbExtract(flags *flag.FlagSet) func([]string) error {
+	casPartFile := flags.String("caspart", "", "`package` containing the cas parts and object definitions")
+	thumbFile := flags.String("thumbs", "", "`package` containing the thumbnails")
+	gameDir := flags.String("game", "", "game installation `directory` to extract every pack from")
+	exportDir := flags.String("out", "", "output `directory`")
+	strict := flags.Bool("strict", false, "stop at the first resource that fails instead of listing failures in "+failures.ReportFile)
+	instance := flags.Bool("instance", false, "include the instance id in file names when the template does not already")
+	index := flags.String("index", thumbextractor.IndexCSV, "index `format`: "+strings.Join(thumbextractor.IndexFormats, ", "))
+	types := flags.String("types", "", "comma separated thumbnail resource `types` to extract, names or hexadecimal ids: "+strings.Join(thumbextractor.ChoiceNames(thumbextractor.Types), ", ")+", default cas part and body part thumbnails for cas parts and object thumbnails for objects")
+	groups := flags.String("groups", "", "comma separated thumbnail `groups` to extract, names, hexadecimal ids or "+thumbextractor.AnyGroup+": "+strings.Join(thumbextractor.ChoiceNames(thumbextractor.Groups), ", ")+", default portrait groups for cas parts and any group for objects")
+	objects := flags.Bool("objects", false, "also extract object definition and catalog thumbnails")
+	layout := flags.String("naming", naming.ThumbnailLayouts[0].Name, "file naming `template` or layout: "+strings.Join(naming.LayoutNames(naming.ThumbnailLayouts), ", ")+", placeholders: {"+strings.Join(naming.Placeholders, "}, {")+"}")
+
+	return func(args []string) error {
+		if *gameDir == "" {
+			if err := required("caspart", *casPartFile); err != nil {
+				return err
+			}
+			if err := required("thumbs", *thumbFile); err != nil {
+				return err
+			}
+		} else if *casPartFile != "" || *thumbFile != "" {
+			return usagef("--game cannot be combined with --caspart or --thumbs")
+		}
+		if err := required("out", *exportDir); err != nil {
+			return err
+		}
+
+		if !thumbextractor.IsIndexFormat(*index) {
+			return usagef("unknown index format %q", *index)
+		}
+
+		typeList, err := thumbextractor.ParseTypes(*types)
+		if err != nil {
+			return usagef("%v", err)
+		}
+		groupList, anyGroup, err := thumbextractor.ParseGroups(*groups)
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		options := thumbextractor.Options{
+			CasPartFile: *casPartFile,
+			ThumbFile:   *thumbFile,
+			GameDir:     *gameDir,
+			ExportDir:   *exportDir,
+			Naming:      *layout,
+			Index:       *index,
+			Instance:    *instance,
+			Types:       typeList,
+			Groups:      groupList,
+			AnyGroup:    anyGroup,
+			Objects:     *objects,
+			Policy:      failures.Strict(*strict),
+		}
+
+		ctx, cancel := interruptible()
+		defer cancel()
+
+		printer := new(progressPrinter)
+		result, err := thumbextractor.Extract(ctx, options, func(e failures.Event) {
+			printer.print(string(e.Phase), e)
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Install != nil {
+			fmt.Println(result.Install.Report())
+		}
+		fmt.Printf("Failures: %v.\n", result.Failures.Summary())
+		fmt.Printf("Extraction completed, %v thumbnails extracted, %v renamed to avoid collisions.\n", result.Written, result.Renamed)
+		return nil
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Fogity/TS4Tools/logging"
+)
+
+const (
+	exitSuccess = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+type command struct {
+	name, args, summary string
+	setup               func(flags *flag.FlagSet) func(args []string) error
+}
+
+var commands []*command
+
+func register(c *command) {
+	commands = append(commands, c)
+}
+
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, a ...interface{}) error {
+	return &usageError{fmt.Sprintf(format, a...)}
+}
+
+func required(name, value string) error {
+	if value == "" {
+		return usagef("--%v must be specified", name)
+	}
+	return nil
+}
+
+const progressInterval = time.Second
+
+type progressPrinter struct {
+	last  time.Time
+	phase string
+}
+
+func (p *progressPrinter) print(phase string, event fmt.Stringer) {
+	now := time.Now()
+	if phase == p.phase && now.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = now
+	p.phase = phase
+	fmt.Println(event)
+}
+
+func interruptible() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage:\n  ts4tools [global options] <command> [options] [arguments]\n\nCommands:\n")
+	sorted := append([]*command(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	for _, c := range sorted {
+		summary := strings.Replace(c.summary, "\n", "\n"+strings.Repeat(" ", 20), -1)
+		fmt.Fprintf(w, "  %-16v  %v\n", c.name, summary)
+	}
+	fmt.Fprintf(w, "\nGlobal options:\n")
+	flags, _ := globalFlags()
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+	fmt.Fprintf(w, "\nRun 'ts4tools help <command>' for more information on a command.\n")
+}
+
+type logOptions struct {
+	level, file string
+	json        bool
+}
+
+func globalFlags() (*flag.FlagSet, *logOptions) {
+	o := new(logOptions)
+	flags := flag.NewFlagSet("ts4tools", flag.ContinueOnError)
+	flags.StringVar(&o.level, "log-level", "warn", "minimum `level` of logged messages: debug, info, warn or error")
+	flags.BoolVar(&o.json, "log-json", false, "log messages to stderr as JSON lines")
+	flags.StringVar(&o.file, "log-file", "", "also log to a rotating `file`, at debug level")
+	return flags, o
+}
+
+func setupLogging(o *logOptions) (func(), error) {
+	level, err := logging.ParseLevel(o.level)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := logging.NewTextSink(os.Stderr)
+	if o.json {
+		sink = logging.NewJSONSink(os.Stderr)
+	}
+	sinks := []logging.Sink{logging.AtLevel(level, sink)}
+	closer := func() {}
+
+	if o.file != "" {
+		file, err := logging.OpenRotating(o.file, logging.DefaultMaxSize, logging.DefaultBackups)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, logging.NewTextSink(file))
+		closer = func() { file.Close() }
+		level = logging.LevelDebug
+	}
+
+	logging.SetDefault(logging.New(level, sinks...))
+	return closer, nil
+}
+
+func (c *command) flagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	flags.Usage = func() {
+		w := flags.Output()
+		fmt.Fprintf(w, "Usage:\n  ts4tools %v %v\n\n%v\n", c.name, c.args, c.summary)
+		hasFlags := false
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(w, "\nOptions:\n")
+			flags.PrintDefaults()
+		}
+	}
+	return flags
+}
+
+func (c *command) run(args []string) int {
+	flags := c.flagSet()
+	run := c.setup(flags)
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitSuccess
+		}
+		return exitUsage
+	}
+
+	err := run(flags.Args())
+	if err == nil {
+		return exitSuccess
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		flags.Usage()
+		return exitUsage
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return exitFailure
+}
+
+func find(args []string) (*command, []string) {
+	for n := len(args); n > 0; n-- {
+		name := strings.Join(args[:n], " ")
+		for _, c := range commands {
+			if c.name == name {
+				return c, args[n:]
+			}
+		}
+	}
+	return nil, args
+}
+
+func help(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return exitSuccess
+	}
+
+	c, rest := find(args)
+	if c == nil || len(rest) != 0 {
+		fmt.Fprintf(os.Stderr, "Unknown command: %v\n", strings.Join(args, " "))
+		return exitUsage
+	}
+
+	flags := c.flagSet()
+	c.setup(flags)
+	flags.SetOutput(os.Stdout)
+	flags.Usage()
+	return exitSuccess
+}
+
+func main() {
+	os.Exit(start(os.Args[1:]))
+}
+
+func start(args []string) int {
+	flags, o := globalFlags()
+	flags.Usage = func() { printUsage(flags.Output()) }
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitSuccess
+		}
+		return exitUsage
+	}
+	args = flags.Args()
+
+	closeLog, err := setupLogging(o)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	defer closeLog()
+
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		return help(args[1:])
+	}
+
+	c, rest := find(args)
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command: %v\n\n", strings.Join(args, " "))
+		printUsage(os.Stderr)
+		return exitUsage
+	}
+
+	return c.run(rest)
+}
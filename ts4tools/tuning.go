@@ -0,0 +1,95 @@
+/*
+Copyright 2015 Henrik Rostedt <https://github.com/Fogity/>
+
+This file is part of TS4Tools.
+
+TS4Tools is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+TS4Tools is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with TS4Tools.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/Fogity/TS4Tools/testertoolbox/failures"
+	"github.com/Fogity/TS4Tools/testertoolbox/naming"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningextractor"
+	"github.com/Fogity/TS4Tools/testertoolbox/tuningformat"
+)
+
+func init() {
+	register(&command{
+		name:    "tuning extract",
+		args:    "--game DIR --export DIR [--from-game] [--lang LANGUAGES] [--format xml|json|yaml]\n    [--naming TEMPLATE] [--incremental] [--by-pack] [--jobs N] [--strict]",
+		summary: "Extract tuning XML from the combined tuning of the game packages, or from the\ncombined tuning files in the export directory. Combined tuning files of a pack\nmay be placed in a subdirectory named after the pack.\nLanguages: " + strings.Join(tuningextractor.Languages, ", ") + ".",
+		setup:   tuningExtract,
+	})
+}
+
+func tuningExtract(flags *flag.FlagSet) func([]string) error {
+	gameDir := flags.String("game", "", "game installation `directory`")
+	exportDir := flags.String("export", "", "`directory` containing the combined tuning files, also used for output")
+	lang := flags.String("lang", tuningextractor.DefaultLanguage, "comma separated string table `languages`, more than one are written side by side")
+	format := flags.String("format", tuningformat.FormatXML, "output `format`: "+strings.Join(tuningformat.Formats, ", "))
+	layout := flags.String("naming", naming.TuningLayouts[0].Name, "file naming `template` or layout: "+strings.Join(naming.LayoutNames(naming.TuningLayouts), ", ")+", placeholders: {"+strings.Join(naming.Placeholders, "}, {")+"}")
+	strict := flags.Bool("strict", false, "stop at the first resource that fails instead of listing failures in "+failures.ReportFile)
+	fromGame := flags.Bool("from-game", false, "read the combined tuning from the game packages instead of the export directory")
+	incremental := flags.Bool("incremental", false, "skip rendering and writing instances unchanged since the last extraction")
+	byPack := flags.Bool("by-pack", false, "write the tuning of each pack to its own directory")
+	jobs := flags.Int("jobs", 0, "`number` of files to write in parallel, defaults to the number of CPUs")
+
+	return func(args []string) error {
+		if err := required("game", *gameDir); err != nil {
+			return err
+		}
+		if err := required("export", *exportDir); err != nil {
+			return err
+		}
+		if !tuningformat.IsFormat(*format) {
+			return usagef("unknown format %q", *format)
+		}
+
+		options := tuningextractor.Options{
+			GameDir:     *gameDir,
+			ExportDir:   *exportDir,
+			Languages:   tuningextractor.ParseLanguages(*lang),
+			Format:      *format,
+			Naming:      *layout,
+			Policy:      failures.Strict(*strict),
+			Concurrency: *jobs,
+			ByPack:      *byPack,
+			FromGame:    *fromGame,
+			Incremental: *incremental,
+		}
+
+		ctx, cancel := interruptible()
+		defer cancel()
+
+		printer := new(progressPrinter)
+		result, err := tuningextractor.Extract(ctx, options, func(e failures.Event) {
+			printer.print(string(e.Phase), e)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(result.Install.Report())
+		fmt.Printf("Tuning changes: %v.\n", result.Changes)
+		fmt.Printf("Failures: %v.\n", result.Failures.Summary())
+		fmt.Printf("Extraction completed, %v files extracted, %v unchanged, %v renamed to avoid collisions.\n", result.Written, result.Skipped, result.Renamed)
+		return nil
+	}
+}